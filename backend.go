@@ -0,0 +1,60 @@
+package uinput
+
+import (
+	"context"
+	"io"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// backend is the device file all events are written to and all requests are issued on. Usually, this is the uinput
+// device file, but a FakeBackend may be used instead in order to test code that uses this package.
+type backend interface {
+	io.ReadWriteCloser
+
+	// ioctl issues a request that takes an integer argument.
+	ioctl(cmd uintptr, arg uintptr) error
+
+	// ioctlPtr issues a request that takes a pointer to a struct or a buffer as argument.
+	ioctlPtr(cmd uintptr, ptr unsafe.Pointer) error
+
+	// waitUntilReady blocks until a newly created device may be used.
+	waitUntilReady(ctx context.Context) error
+}
+
+// fileBackend is the backend of all devices that are created using the uinput device file.
+type fileBackend struct {
+	file *os.File
+}
+
+func (b *fileBackend) Read(p []byte) (int, error) {
+	return b.file.Read(p)
+}
+
+func (b *fileBackend) Write(p []byte) (int, error) {
+	return b.file.Write(p)
+}
+
+func (b *fileBackend) Close() error {
+	return b.file.Close()
+}
+
+// original function taken from: https://github.com/tianon/debian-golang-pty/blob/master/ioctl.go
+func (b *fileBackend) ioctl(cmd uintptr, arg uintptr) error {
+	return control(b.file, cmd, func(fd uintptr) syscall.Errno {
+		_, _, errorCode := syscall.Syscall(syscall.SYS_IOCTL, fd, cmd, arg)
+		return errorCode
+	})
+}
+
+func (b *fileBackend) ioctlPtr(cmd uintptr, ptr unsafe.Pointer) error {
+	return control(b.file, cmd, func(fd uintptr) syscall.Errno {
+		_, _, errorCode := syscall.Syscall(syscall.SYS_IOCTL, fd, cmd, uintptr(ptr))
+		return errorCode
+	})
+}
+
+func (b *fileBackend) waitUntilReady(ctx context.Context) error {
+	return waitUntilReady(ctx, b)
+}
@@ -0,0 +1,114 @@
+package uinput
+
+import (
+	"fmt"
+
+	"github.com/bendahl/uinput/evdev"
+)
+
+// deviceSource is an input device whose identity and capabilities may be cloned. It is implemented by *evdev.Device.
+type deviceSource interface {
+	ID() (evdev.ID, error)
+	Properties() ([]uint16, error)
+	EventTypes() ([]uint16, error)
+	Codes(evType uint16) ([]uint16, error)
+	AbsInfo(axis uint16) (evdev.AbsInfo, error)
+}
+
+// CloneDevice will create a generic device that has the same identity, properties and capabilities as the input device
+// with the given event node (/dev/input/eventN), for example in order to proxy the events of a physical gamepad. The
+// ranges of all absolute axes are cloned as well. Autorepeat (EV_REP) and force feedback (EV_FF) are not cloned.
+// The given options are applied after the identity of the source, so they may override it. Properties that are
+// given using WithProperties are added to those of the source; they cannot be removed.
+func CloneDevice(uinputPath string, sourceEventNode string, name []byte, opts ...Option) (Device, error) {
+	err := validateDevicePath(uinputPath, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := evdev.Open(sourceEventNode)
+	if err != nil {
+		return nil, fmt.Errorf("could not open source device: %w", err)
+	}
+	defer source.Close()
+
+	builder, cloneOpts, err := cloneCapabilities(uinputPath, source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query source device: %w", err)
+	}
+	return builder.Name(name).Create(append(cloneOpts, opts...)...)
+}
+
+// cloneCapabilities returns a builder that declares all capabilities of the source, along with the options that apply
+// its identity and properties.
+func cloneCapabilities(path string, source deviceSource) (*DeviceBuilder, []Option, error) {
+	id, err := source.ID()
+	if err != nil {
+		return nil, nil, err
+	}
+	props, err := source.Properties()
+	if err != nil {
+		return nil, nil, err
+	}
+	evTypes, err := source.EventTypes()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	builder := NewDeviceBuilder(path)
+	for _, evType := range evTypes {
+		switch evType {
+		case EvKey, EvRel, EvAbs, EvMsc, EvSw, EvLed, EvSnd:
+		default:
+			continue
+		}
+		codes, err := source.Codes(evType)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, code := range codes {
+			err = cloneCode(builder, source, evType, code)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	properties := make([]Property, len(props))
+	for i, prop := range props {
+		properties[i] = Property(prop)
+	}
+	opts := []Option{
+		WithBusType(BusType(id.BusType)),
+		WithVendor(id.Vendor),
+		WithProduct(id.Product),
+		WithVersion(id.Version),
+		WithProperties(properties...),
+	}
+	return builder, opts, nil
+}
+
+func cloneCode(builder *DeviceBuilder, source deviceSource, evType uint16, code uint16) error {
+	switch evType {
+	case EvKey:
+		builder.Keys(int(code))
+	case EvRel:
+		builder.RelAxes(int(code))
+	case EvAbs:
+		info, err := source.AbsInfo(code)
+		if err != nil {
+			return err
+		}
+		builder.AbsAxis(int(code), info.Minimum, info.Maximum, info.Fuzz, info.Flat)
+		builder.AbsResolution(int(code), info.Resolution)
+	case EvMsc:
+		builder.MiscEvents(int(code))
+	case EvSw:
+		builder.Switches(int(code))
+	case EvLed:
+		builder.LEDs(int(code))
+	case EvSnd:
+		builder.Sounds(int(code))
+	}
+	return nil
+}
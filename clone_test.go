@@ -0,0 +1,156 @@
+package uinput
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/bendahl/uinput/evdev"
+)
+
+type sourceDevice struct {
+	id      evdev.ID
+	props   []uint16
+	codes   map[uint16][]uint16
+	absInfo map[uint16]evdev.AbsInfo
+	err     error
+}
+
+func (s *sourceDevice) ID() (evdev.ID, error) {
+	return s.id, nil
+}
+
+func (s *sourceDevice) Properties() ([]uint16, error) {
+	return s.props, nil
+}
+
+func (s *sourceDevice) EventTypes() ([]uint16, error) {
+	var evTypes []uint16
+	for evType := uint16(0); evType <= EvFF; evType++ {
+		if _, ok := s.codes[evType]; ok {
+			evTypes = append(evTypes, evType)
+		}
+	}
+	return evTypes, nil
+}
+
+func (s *sourceDevice) Codes(evType uint16) ([]uint16, error) {
+	return s.codes[evType], nil
+}
+
+func (s *sourceDevice) AbsInfo(axis uint16) (evdev.AbsInfo, error) {
+	return s.absInfo[axis], s.err
+}
+
+func TestCloneCapabilities(t *testing.T) {
+	source := &sourceDevice{
+		id:    evdev.ID{BusType: 0x05, Vendor: 0x054c, Product: 0x09cc, Version: 0x8100},
+		props: []uint16{uint16(PropPointer)},
+		codes: map[uint16][]uint16{
+			EvKey: {ButtonSouth, ButtonEast},
+			EvAbs: {AbsX, AbsHat0X},
+			EvMsc: {0x04},
+			EvLed: {LedNumLock},
+			EvFF:  {ffRumble},
+		},
+		absInfo: map[uint16]evdev.AbsInfo{
+			AbsX:     {Minimum: 0, Maximum: 255, Fuzz: 2, Flat: 8, Resolution: 4},
+			AbsHat0X: {Minimum: -1, Maximum: 1},
+		},
+	}
+
+	builder, opts, err := cloneCapabilities("/dev/uinput", source)
+	if err != nil {
+		t.Fatalf("Failed to clone capabilities. Last error was: %s\n", err)
+	}
+
+	fake := NewFakeBackend()
+	dev, err := builder.Name([]byte("Clone")).Create(append(opts, WithFakeBackend(fake))...)
+	if err != nil {
+		t.Fatalf("Failed to create the clone. Last error was: %s\n", err)
+	}
+	defer dev.Close()
+
+	var evTypes []uintptr
+	var absSetups []uinputAbsSetup
+	var setup []byte
+	for _, request := range fake.Ioctls() {
+		switch request.Request {
+		case uiSetEvBit:
+			evTypes = append(evTypes, request.Arg)
+		case uiAbsSetup:
+			var abs uinputAbsSetup
+			_ = binary.Read(bytes.NewReader(request.Data), binary.LittleEndian, &abs)
+			absSetups = append(absSetups, abs)
+		case uiDevSetup:
+			setup = request.Data
+		}
+	}
+
+	if expected := []uintptr{EvKey, EvAbs, EvMsc, EvLed}; !reflect.DeepEqual(expected, evTypes) {
+		t.Fatalf("Expected event types %v to be registered, but got %v", expected, evTypes)
+	}
+	if len(absSetups) != 2 {
+		t.Fatalf("Expected 2 absolute axes to be set up, but got %d", len(absSetups))
+	}
+	if info := absSetups[0].AbsInfo; absSetups[0].Code != AbsX || info.Maximum != 255 || info.Fuzz != 2 || info.Flat != 8 || info.Resolution != 4 {
+		t.Fatalf("Unexpected setup of ABS_X: %+v", absSetups[0])
+	}
+	if vendor := binary.LittleEndian.Uint16(setup[2:]); vendor != 0x054c {
+		t.Fatalf("Expected the vendor of the source to be cloned, but got %#04x", vendor)
+	}
+
+	err = dev.WriteEvents(Event{Type: EvAbs, Code: AbsHat0X, Value: -1}, Event{Type: EvKey, Code: ButtonSouth, Value: 1})
+	if err != nil {
+		t.Fatalf("Failed to write events to the clone. Last error was: %s\n", err)
+	}
+}
+
+func TestCloneCapabilitiesFailsIfSourceCanNotBeQueried(t *testing.T) {
+	source := &sourceDevice{codes: map[uint16][]uint16{EvAbs: {AbsX}}, err: errors.New("no such device")}
+
+	_, _, err := cloneCapabilities("/dev/uinput", source)
+	if err == nil || err.Error() != "no such device" {
+		t.Fatalf("Expected the error of the source to be returned, but got: %v", err)
+	}
+}
+
+func TestCloneDeviceFailsOnMissingSource(t *testing.T) {
+	_, err := CloneDevice("/dev/uinput", "/dev/input/no-such-event-node", []byte("Clone"), WithFakeBackend(NewFakeBackend()))
+	if err == nil {
+		t.Fatalf("Expected cloning to fail due to a missing source device, but got no error.")
+	}
+}
+
+func TestCloneGamepad(t *testing.T) {
+	vg, err := CreateGamepad("/dev/uinput", []byte("Test Gamepad"), 0xDEAD, 0xBEEF)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+	defer vg.Close()
+
+	source, err := vg.Info()
+	if err != nil {
+		t.Fatalf("Failed to fetch device info. Last error was: %s\n", err)
+	}
+
+	clone, err := CloneDevice("/dev/uinput", source.EventNode, []byte("Test Gamepad Clone"))
+	if err != nil {
+		t.Fatalf("Failed to clone the virtual gamepad. Last error was: %s\n", err)
+	}
+	defer clone.Close()
+
+	info, err := clone.Info()
+	if err != nil {
+		t.Fatalf("Failed to fetch device info. Last error was: %s\n", err)
+	}
+	if info.ID != source.ID {
+		t.Fatalf("Expected id %+v, but got %+v", source.ID, info.ID)
+	}
+	if !reflect.DeepEqual(source.Capabilities.Keys, info.Capabilities.Keys) ||
+		!reflect.DeepEqual(source.Capabilities.AbsAxes, info.Capabilities.AbsAxes) {
+		t.Fatalf("Expected capabilities %+v, but got %+v", source.Capabilities, info.Capabilities)
+	}
+}
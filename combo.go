@@ -0,0 +1,81 @@
+package uinput
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A KeyCombo is a key that is pressed while holding down a number of modifier keys, like Ctrl+Alt+T. Combos are
+// usually created using ParseCombo and sent using Keyboard.PressCombo.
+type KeyCombo struct {
+	// Modifiers are pressed in the given order before the key is pressed, and released in reverse order afterwards.
+	Modifiers []int
+	Key       int
+}
+
+// modifierNames maps the names of modifier keys to their key codes. Names without a side refer to the left key.
+var modifierNames = map[string]int{
+	"ctrl": KeyLeftctrl, "control": KeyLeftctrl, "leftctrl": KeyLeftctrl, "rightctrl": KeyRightctrl,
+	"shift": KeyLeftshift, "leftshift": KeyLeftshift, "rightshift": KeyRightshift,
+	"alt": KeyLeftalt, "option": KeyLeftalt, "leftalt": KeyLeftalt, "rightalt": KeyRightalt, "altgr": KeyRightalt,
+	"super": KeyLeftmeta, "meta": KeyLeftmeta, "cmd": KeyLeftmeta, "command": KeyLeftmeta, "win": KeyLeftmeta,
+	"leftmeta": KeyLeftmeta, "rightmeta": KeyRightmeta,
+}
+
+// comboAliases maps abbreviations and alternative names of keys to their key codes. All other keys are resolved
+// using KeyByName.
+var comboAliases = map[string]int{
+	"escape": KeyEsc, "return": KeyEnter, "del": KeyDelete, "ins": KeyInsert, "pgup": KeyPageup, "pgdn": KeyPagedown,
+	"print": KeySysrq, "printscreen": KeySysrq, "menu": KeyCompose, "period": KeyDot,
+}
+
+// ParseCombo parses a key combo like "ctrl+alt+t", or a sequence of combos separated by spaces like "ctrl+k ctrl+c".
+// Key names are case insensitive. Besides the names of the modifier keys (ctrl, shift, alt, altgr and super, which is
+// also available as meta, cmd and win), all keys may be given by their name without the KEY_ prefix, for example
+// "a", "f4", "enter", "pageup" or "volumeup", or by their full name as accepted by KeyByName. Some common
+// abbreviations like "del" or "pgup" are supported as well.
+func ParseCombo(s string) ([]KeyCombo, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("invalid key combo %q: no keys given", s)
+	}
+
+	combos := make([]KeyCombo, 0, len(fields))
+	for _, field := range fields {
+		names := strings.Split(field, "+")
+		var combo KeyCombo
+		for i, name := range names {
+			if i < len(names)-1 {
+				modifier, ok := modifierNames[strings.ToLower(name)]
+				if !ok {
+					return nil, fmt.Errorf("invalid key combo %q: %q is not a modifier key", s, name)
+				}
+				combo.Modifiers = append(combo.Modifiers, modifier)
+				continue
+			}
+
+			key, ok := comboKey(name)
+			if !ok {
+				return nil, fmt.Errorf("invalid key combo %q: unknown key %q", s, name)
+			}
+			combo.Key = key
+		}
+		combos = append(combos, combo)
+	}
+	return combos, nil
+}
+
+// comboKey returns the key code of the given key name.
+func comboKey(name string) (int, bool) {
+	lower := strings.ToLower(name)
+	if key, ok := modifierNames[lower]; ok {
+		return key, true
+	}
+	if key, ok := comboAliases[lower]; ok {
+		return key, true
+	}
+	if key, ok := KeyByName(name); ok {
+		return key, true
+	}
+	return KeyByName("KEY_" + name)
+}
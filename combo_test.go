@@ -0,0 +1,111 @@
+package uinput
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseCombo(t *testing.T) {
+	tests := []struct {
+		combo    string
+		expected []KeyCombo
+	}{
+		{"t", []KeyCombo{{Key: KeyT}}},
+		{"ctrl+alt+t", []KeyCombo{{Modifiers: []int{KeyLeftctrl, KeyLeftalt}, Key: KeyT}}},
+		{"Ctrl+Shift+Esc", []KeyCombo{{Modifiers: []int{KeyLeftctrl, KeyLeftshift}, Key: KeyEsc}}},
+		{"super+l", []KeyCombo{{Modifiers: []int{KeyLeftmeta}, Key: KeyL}}},
+		{"meta+l", []KeyCombo{{Modifiers: []int{KeyLeftmeta}, Key: KeyL}}},
+		{"cmd+q", []KeyCombo{{Modifiers: []int{KeyLeftmeta}, Key: KeyQ}}},
+		{"altgr+e", []KeyCombo{{Modifiers: []int{KeyRightalt}, Key: KeyE}}},
+		{"alt+f4", []KeyCombo{{Modifiers: []int{KeyLeftalt}, Key: KeyF4}}},
+		{"shift+pageup", []KeyCombo{{Modifiers: []int{KeyLeftshift}, Key: KeyPageup}}},
+		{"super", []KeyCombo{{Key: KeyLeftmeta}}},
+		{"volumeup", []KeyCombo{{Key: KeyVolumeup}}},
+		{"ctrl+KEY_F5", []KeyCombo{{Modifiers: []int{KeyLeftctrl}, Key: KeyF5}}},
+		{"shift+KeyDelete", []KeyCombo{{Modifiers: []int{KeyLeftshift}, Key: KeyDelete}}},
+		{"alt+PrintScreen", []KeyCombo{{Modifiers: []int{KeyLeftalt}, Key: KeySysrq}}},
+		{"ctrl+k ctrl+c", []KeyCombo{
+			{Modifiers: []int{KeyLeftctrl}, Key: KeyK},
+			{Modifiers: []int{KeyLeftctrl}, Key: KeyC},
+		}},
+		{"  ctrl+x   1 ", []KeyCombo{{Modifiers: []int{KeyLeftctrl}, Key: KeyX}, {Key: Key1}}},
+	}
+
+	for _, test := range tests {
+		actual, err := ParseCombo(test.combo)
+		if err != nil {
+			t.Fatalf("Failed to parse %q: %v", test.combo, err)
+		}
+		if !reflect.DeepEqual(test.expected, actual) {
+			t.Fatalf("Combo %q\nExpected: %+v\nActual: %+v", test.combo, test.expected, actual)
+		}
+	}
+}
+
+func TestParseComboRejectsInvalidCombos(t *testing.T) {
+	for _, combo := range []string{"", " ", "ctrl+", "+t", "ctrl++t", "t+ctrl", "ctrl+k+c", "ctrl+nosuchkey"} {
+		_, err := ParseCombo(combo)
+		if err == nil {
+			t.Fatalf("Expected %q to be rejected", combo)
+		}
+	}
+}
+
+func TestKeyboardPressCombo(t *testing.T) {
+	fake := NewFakeBackend()
+	vk, err := CreateKeyboard("/dev/uinput", []byte("Test Keyboard"), WithFakeBackend(fake))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	defer vk.Close()
+
+	combos, err := ParseCombo("ctrl+alt+t ctrl+k")
+	if err != nil {
+		t.Fatalf("Failed to parse combo: %v", err)
+	}
+	err = vk.PressCombo(combos...)
+	if err != nil {
+		t.Fatalf("Failed to press combo. Last error was: %s\n", err)
+	}
+
+	expected := keyEvents(
+		KeyLeftctrl, KeyLeftalt, KeyT, -KeyT, -KeyLeftalt, -KeyLeftctrl,
+		KeyLeftctrl, KeyK, -KeyK, -KeyLeftctrl)
+	if actual := fake.Events(); !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, actual)
+	}
+}
+
+func TestKeyboardPressComboFailsOnKeysOutOfRange(t *testing.T) {
+	fake := NewFakeBackend()
+	vk, err := CreateKeyboard("/dev/uinput", []byte("Test Keyboard"), WithFakeBackend(fake))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	defer vk.Close()
+
+	err = vk.PressCombo(KeyCombo{Key: KeyA}, KeyCombo{Modifiers: []int{KeyLeftctrl}, Key: keyMax + 1})
+	if !errors.Is(err, ErrKeyCodeOutOfRange) {
+		t.Fatalf("Expected ErrKeyCodeOutOfRange, but got: %v", err)
+	}
+	if events := fake.Events(); len(events) != 0 {
+		t.Fatalf("Expected nothing to be pressed, but got %+v", events)
+	}
+}
+
+func TestKeyboardPressComboFailsIfDeviceIsClosed(t *testing.T) {
+	vk, err := CreateKeyboard("/dev/uinput", []byte("Test Keyboard"), WithFakeBackend(NewFakeBackend()))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	err = vk.Close()
+	if err != nil {
+		t.Fatalf("Failed to close device. Last error was: %s\n", err)
+	}
+
+	err = vk.PressCombo(KeyCombo{Modifiers: []int{KeyLeftctrl}, Key: KeyC})
+	if !errors.Is(err, ErrDeviceClosed) {
+		t.Fatalf("Expected pressing a combo on a closed device to fail with ErrDeviceClosed, but got: %v", err)
+	}
+}
@@ -0,0 +1,366 @@
+package uinput
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// A Device is a generic input device whose capabilities have been declared using a DeviceBuilder.
+// Only codes that have been registered upon creation of the device may be sent.
+// For details on the available codes see: https://www.kernel.org/doc/Documentation/input/event-codes.txt
+type Device interface {
+	// KeyDown will send a key press event for the given key or button code.
+	// Note that the key will be "held down" until "KeyUp" is called.
+	KeyDown(code int) error
+
+	// KeyUp will send a key release event for the given key or button code.
+	KeyUp(code int) error
+
+	// RelMove will send a relative axis event (EV_REL) with the given delta.
+	RelMove(axis int, delta int32) error
+
+	// AbsMove will send an absolute axis event (EV_ABS) with the given value.
+	AbsMove(axis int, value int32) error
+
+	// SendMisc will send a miscellaneous event (EV_MSC), for example MSC_SCAN.
+	SendMisc(code int, value int32) error
+
+	// SetSwitch will set the state of the given switch (EV_SW).
+	SetSwitch(code int, on bool) error
+
+	// WriteEvents will write the given events followed by a sync event (SYN_REPORT) as a single frame. All events
+	// need to refer to registered codes, sync events are not allowed.
+	WriteEvents(events ...Event) error
+
+	// FetchSyspath will return the syspath to the device file.
+	FetchSyspath() (string, error)
+
+	// ReleaseAll will release all keys and buttons that are held down and re-center all hats. This is done automatically
+	// when the device is closed.
+	ReleaseAll() error
+
+	// Info will return information about the device as seen by the kernel, such as its syspath and event node.
+	Info() (DeviceInfo, error)
+
+	io.Closer
+}
+
+// DeviceBuilder collects the capabilities of a generic input device. Use NewDeviceBuilder to obtain a builder, declare
+// the required capabilities and call Create to create the device.
+//
+// Example (a mouse with an additional absolute pressure axis):
+//
+//	dev, err := NewDeviceBuilder("/dev/uinput").
+//		Name([]byte("Pressure Mouse")).
+//		Keys(ButtonLeft, ButtonRight).
+//		RelAxes(RelX, RelY).
+//		AbsAxis(AbsPressure, 0, 255, 0, 0).
+//		Create()
+type DeviceBuilder struct {
+	path       string
+	name       []byte
+	vendor     uint16
+	product    uint16
+	keys       []int
+	relAxes    []int
+	absAxes    []absAxis
+	miscEvents []int
+	switches   []int
+	leds       []int
+	sounds     []int
+}
+
+// NewDeviceBuilder will create a new builder for a device that is created using the given uinput device path.
+func NewDeviceBuilder(path string) *DeviceBuilder {
+	return &DeviceBuilder{path: path, vendor: 0x4711, product: 0x0818}
+}
+
+// Name sets the name of the device.
+func (b *DeviceBuilder) Name(name []byte) *DeviceBuilder {
+	b.name = name
+	return b
+}
+
+// ID sets the vendor and product id of the device.
+func (b *DeviceBuilder) ID(vendor uint16, product uint16) *DeviceBuilder {
+	b.vendor = vendor
+	b.product = product
+	return b
+}
+
+// Keys registers the given key and button codes (EV_KEY).
+func (b *DeviceBuilder) Keys(codes ...int) *DeviceBuilder {
+	b.keys = append(b.keys, codes...)
+	return b
+}
+
+// RelAxes registers the given relative axes (EV_REL).
+func (b *DeviceBuilder) RelAxes(codes ...int) *DeviceBuilder {
+	b.relAxes = append(b.relAxes, codes...)
+	return b
+}
+
+// AbsAxis registers an absolute axis (EV_ABS) along with its range. Values within fuzz of the previous value are
+// filtered by the kernel and values within flat around the center are reported as the center value.
+func (b *DeviceBuilder) AbsAxis(code int, min int32, max int32, fuzz int32, flat int32) *DeviceBuilder {
+	b.absAxes = append(b.absAxes, absAxis{code: code, min: min, max: max, fuzz: fuzz, flat: flat})
+	return b
+}
+
+// AbsResolution sets the resolution of an absolute axis that has been registered using AbsAxis. The resolution is
+// given in units per millimeter (units per radian for rotational axes). Note that the resolution is only applied on
+// kernels that support uinput version 5 (linux 4.5 and later).
+func (b *DeviceBuilder) AbsResolution(code int, resolution int32) *DeviceBuilder {
+	for i := range b.absAxes {
+		if b.absAxes[i].code == code {
+			b.absAxes[i].resolution = resolution
+		}
+	}
+	return b
+}
+
+// MiscEvents registers the given miscellaneous event codes (EV_MSC).
+func (b *DeviceBuilder) MiscEvents(codes ...int) *DeviceBuilder {
+	b.miscEvents = append(b.miscEvents, codes...)
+	return b
+}
+
+// Switches registers the given switch codes (EV_SW).
+func (b *DeviceBuilder) Switches(codes ...int) *DeviceBuilder {
+	b.switches = append(b.switches, codes...)
+	return b
+}
+
+// LEDs registers the given LED codes (EV_LED).
+func (b *DeviceBuilder) LEDs(codes ...int) *DeviceBuilder {
+	b.leds = append(b.leds, codes...)
+	return b
+}
+
+// Sounds registers the given sound codes (EV_SND), for example SND_BELL.
+func (b *DeviceBuilder) Sounds(codes ...int) *DeviceBuilder {
+	b.sounds = append(b.sounds, codes...)
+	return b
+}
+
+// Create will create the device with all capabilities declared so far.
+func (b *DeviceBuilder) Create(opts ...Option) (Device, error) {
+	err := validateDevicePath(b.path, opts)
+	if err != nil {
+		return nil, err
+	}
+	err = validateUinputName(b.name)
+	if err != nil {
+		return nil, err
+	}
+	err = b.validateCapabilities()
+	if err != nil {
+		return nil, err
+	}
+
+	fd, err := b.createDevice(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return vDevice{name: b.name, dev: newUinputDevice(fd, b.capabilities(), b.hats()...)}, nil
+}
+
+// capabilityGroups returns the codes of all event types that use a plain list of codes, along with the ioctl
+// request that is used to register a single code.
+func (b *DeviceBuilder) capabilityGroups() []capabilityGroup {
+	var absCodes []int
+	for _, axis := range b.absAxes {
+		absCodes = append(absCodes, axis.code)
+	}
+	return []capabilityGroup{
+		{evType: EvKey, setBit: uiSetKeyBit, max: keyCodeMax, codes: b.keys},
+		{evType: EvRel, setBit: uiSetRelBit, max: relMax, codes: b.relAxes},
+		{evType: EvAbs, setBit: uiSetAbsBit, max: absMax, codes: absCodes},
+		{evType: EvMsc, setBit: uiSetMscBit, max: mscMax, codes: b.miscEvents},
+		{evType: EvSw, setBit: uiSetSwBit, max: swMax, codes: b.switches},
+		{evType: EvLed, setBit: uiSetLedBit, max: ledMax, codes: b.leds},
+		{evType: EvSnd, setBit: uiSetSndBit, max: sndMax, codes: b.sounds},
+	}
+}
+
+type capabilityGroup struct {
+	evType uint16
+	setBit uintptr
+	max    int
+	codes  []int
+}
+
+// rangeError returns an error that matches ErrKeyCodeOutOfRange for keys and ErrAxisOutOfRange for axes.
+func (g capabilityGroup) rangeError(format string, a ...interface{}) error {
+	switch g.evType {
+	case EvKey:
+		return keyCodeError(format, a...)
+	case EvRel, EvAbs:
+		return axisError(format, a...)
+	}
+	return fmt.Errorf(format, a...)
+}
+
+func (b *DeviceBuilder) validateCapabilities() error {
+	empty := true
+	for _, group := range b.capabilityGroups() {
+		for _, code := range group.codes {
+			if code < 0 || code > group.max {
+				return group.rangeError("code %d of event type %d is not in range (maximum is %d)", code, group.evType, group.max)
+			}
+			empty = false
+		}
+	}
+	if empty {
+		return errors.New("device must declare at least one capability")
+	}
+	for _, axis := range b.absAxes {
+		if axis.min > axis.max {
+			return axisError("minimum %d of absolute axis %d is greater than its maximum %d", axis.min, axis.code, axis.max)
+		}
+	}
+	return nil
+}
+
+func (b *DeviceBuilder) capabilities() capabilities {
+	caps := make(capabilities)
+	for _, group := range b.capabilityGroups() {
+		if len(group.codes) > 0 {
+			caps.add(group.evType, group.codes...)
+		}
+	}
+	return caps
+}
+
+// hats returns all hat axes of the device, which are re-centered when everything is released.
+func (b *DeviceBuilder) hats() []uint16 {
+	var hats []uint16
+	for _, axis := range b.absAxes {
+		if axis.code >= AbsHat0X && axis.code <= AbsHat3Y {
+			hats = append(hats, uint16(axis.code))
+		}
+	}
+	return hats
+}
+
+func (b *DeviceBuilder) createDevice(opts []Option) (fd backend, err error) {
+	deviceFile, err := createDeviceFile(b.path, opts)
+	if err != nil {
+		return nil, fmt.Errorf("could not create generic input device: %w", err)
+	}
+
+	for _, group := range b.capabilityGroups() {
+		if len(group.codes) == 0 {
+			continue
+		}
+		err = registerDevice(deviceFile, uintptr(group.evType))
+		if err != nil {
+			// registerDevice closes the device file on failure
+			return nil, fmt.Errorf("failed to register event type %d: %w", group.evType, err)
+		}
+		for _, code := range group.codes {
+			err = ioctl(deviceFile, group.setBit, uintptr(code))
+			if err != nil {
+				_ = deviceFile.Close()
+				return nil, fmt.Errorf("failed to register code %d of event type %d: %w", code, group.evType, err)
+			}
+		}
+	}
+
+	return createUsbDevice(deviceFile,
+		deviceSetup{
+			name: b.name,
+			id: inputID{
+				Bustype: busUsb,
+				Vendor:  b.vendor,
+				Product: b.product,
+				Version: 1},
+			absAxes: b.absAxes}, opts)
+}
+
+type vDevice struct {
+	name []byte
+	dev  *uinputDevice
+}
+
+// KeyDown will send a key press event for the given key or button code.
+func (vd vDevice) KeyDown(code int) error {
+	if err := vd.assertRegistered(EvKey, code); err != nil {
+		return err
+	}
+	return sendBtnEvent(vd.dev, code, btnStatePressed)
+}
+
+// KeyUp will send a key release event for the given key or button code.
+func (vd vDevice) KeyUp(code int) error {
+	if err := vd.assertRegistered(EvKey, code); err != nil {
+		return err
+	}
+	return sendBtnEvent(vd.dev, code, btnStateReleased)
+}
+
+// RelMove will send a relative axis event with the given delta.
+func (vd vDevice) RelMove(axis int, delta int32) error {
+	if err := vd.assertRegistered(EvRel, axis); err != nil {
+		return err
+	}
+	return sendEvent(vd.dev, EvRel, uint16(axis), delta)
+}
+
+// AbsMove will send an absolute axis event with the given value.
+func (vd vDevice) AbsMove(axis int, value int32) error {
+	if err := vd.assertRegistered(EvAbs, axis); err != nil {
+		return err
+	}
+	return sendEvent(vd.dev, EvAbs, uint16(axis), value)
+}
+
+// SendMisc will send a miscellaneous event.
+func (vd vDevice) SendMisc(code int, value int32) error {
+	if err := vd.assertRegistered(EvMsc, code); err != nil {
+		return err
+	}
+	return sendEvent(vd.dev, EvMsc, uint16(code), value)
+}
+
+// SetSwitch will set the state of the given switch.
+func (vd vDevice) SetSwitch(code int, on bool) error {
+	if err := vd.assertRegistered(EvSw, code); err != nil {
+		return err
+	}
+	value := int32(0)
+	if on {
+		value = 1
+	}
+	return sendEvent(vd.dev, EvSw, uint16(code), value)
+}
+
+// ReleaseAll will release everything that is currently held down, see the interface for details.
+func (vd vDevice) ReleaseAll() error {
+	return vd.dev.releaseAll()
+}
+
+// Info will return information about the device as seen by the kernel.
+func (vd vDevice) Info() (DeviceInfo, error) {
+	return vd.dev.info()
+}
+
+// WriteEvents will write the given events as a single frame.
+func (vd vDevice) WriteEvents(events ...Event) error {
+	return vd.dev.writeEvents(events)
+}
+
+// Close will close the device and free resources.
+func (vd vDevice) Close() error {
+	return vd.dev.close()
+}
+
+func (vd vDevice) FetchSyspath() (string, error) {
+	return vd.dev.fetchSyspath()
+}
+
+func (vd vDevice) assertRegistered(evType uint16, code int) error {
+	return vd.dev.capabilities.assertRegistered(evType, code)
+}
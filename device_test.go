@@ -0,0 +1,214 @@
+package uinput
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"syscall"
+	"testing"
+)
+
+func TestGenericDeviceEvents(t *testing.T) {
+	dev, err := NewDeviceBuilder("/dev/uinput").
+		Name([]byte("Test Generic Device")).
+		Keys(ButtonLeft, ButtonRight).
+		RelAxes(RelX, RelY).
+		AbsAxis(AbsZ, 0, 255, 0, 0).
+		MiscEvents(0x04).
+		Switches(0x00).
+		Create()
+	if err != nil {
+		t.Fatalf("Failed to create the generic device. Last error was: %s\n", err)
+	}
+	defer func(dev Device) {
+		err := dev.Close()
+		if err != nil {
+			t.Fatalf("Failed to close device. Last error was: %s\n", err)
+		}
+	}(dev)
+
+	err = dev.KeyDown(ButtonLeft)
+	if err != nil {
+		t.Fatalf("Failed to send key down event. Last error was: %s\n", err)
+	}
+	err = dev.KeyUp(ButtonLeft)
+	if err != nil {
+		t.Fatalf("Failed to send key up event. Last error was: %s\n", err)
+	}
+	err = dev.RelMove(RelX, 10)
+	if err != nil {
+		t.Fatalf("Failed to send relative axis event. Last error was: %s\n", err)
+	}
+	err = dev.AbsMove(AbsZ, 128)
+	if err != nil {
+		t.Fatalf("Failed to send absolute axis event. Last error was: %s\n", err)
+	}
+	err = dev.SendMisc(0x04, 42)
+	if err != nil {
+		t.Fatalf("Failed to send misc event. Last error was: %s\n", err)
+	}
+	err = dev.SetSwitch(0x00, true)
+	if err != nil {
+		t.Fatalf("Failed to send switch event. Last error was: %s\n", err)
+	}
+}
+
+func TestGenericDeviceRejectsUnregisteredCodes(t *testing.T) {
+	dev, err := NewDeviceBuilder("/dev/uinput").
+		Name([]byte("Test Generic Device")).
+		Keys(ButtonLeft).
+		Create()
+	if err != nil {
+		t.Fatalf("Failed to create the generic device. Last error was: %s\n", err)
+	}
+	defer dev.Close()
+
+	err = dev.KeyDown(ButtonRight)
+	if err == nil {
+		t.Fatalf("Expected key down to fail due to unregistered key code, but got no error.")
+	}
+	err = dev.RelMove(RelX, 1)
+	if err == nil {
+		t.Fatalf("Expected relative move to fail due to unregistered axis, but got no error.")
+	}
+}
+
+func TestGenericDeviceSyspath(t *testing.T) {
+	dev, err := NewDeviceBuilder("/dev/uinput").
+		Name([]byte("Test Generic Device")).
+		Keys(KeyA).
+		Create()
+	if err != nil {
+		t.Fatalf("Failed to create the generic device. Last error was: %s\n", err)
+	}
+	defer dev.Close()
+
+	sysPath, err := dev.FetchSyspath()
+	if err != nil {
+		t.Fatalf("Failed to fetch syspath. Last error was: %s\n", err)
+	}
+
+	if sysPath[:32] != "/sys/devices/virtual/input/input" {
+		t.Fatalf("Expected syspath to start with /sys/devices/virtual/input/input, but got %s", sysPath)
+	}
+}
+
+func TestGenericDeviceCreationFailsOnEmptyPath(t *testing.T) {
+	expected := "device path must not be empty"
+	_, err := NewDeviceBuilder("").Name([]byte("GenericDevice")).Keys(KeyA).Create()
+	if err.Error() != expected {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+}
+
+func TestGenericDeviceCreationFailsOnNonExistentPathName(t *testing.T) {
+	path := "/some/bogus/path"
+	_, err := NewDeviceBuilder(path).Name([]byte("GenericDevice")).Keys(KeyA).Create()
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Expected: os.ErrNotExist error\nActual: %s", err)
+	}
+}
+
+func TestGenericDeviceCreationFailsOnWrongPathName(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-device-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer file.Close()
+
+	expected := "failed to register event type 1: invalid file handle returned from ioctl: inappropriate ioctl for device (failed to close device: inappropriate ioctl for device)"
+	_, err = NewDeviceBuilder(file.Name()).Name([]byte("GenericDevice")).Keys(KeyA).Create()
+	if err == nil || !(expected == err.Error()) {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+}
+
+func TestGenericDeviceCreationClosesBackendOnceOnFailure(t *testing.T) {
+	for _, request := range []Ioctl{{Request: uiSetEvBit, Arg: EvKey}, {Request: uiSetKeyBit, Arg: ButtonLeft}} {
+		fake := NewFakeBackend()
+		fake.failRequest(request.Request, request.Arg, syscall.EINVAL)
+		_, err := NewDeviceBuilder("/dev/uinput").Name([]byte("Test Generic Device")).Keys(ButtonLeft).
+			Create(WithFakeBackend(fake))
+		if err == nil {
+			t.Fatalf("Expected device creation to fail if %s fails", ioctlNames[request.Request])
+		}
+		if fake.closeCalls != 1 {
+			t.Fatalf("Expected the backend to be closed once if %s fails, but it was closed %d times", ioctlNames[request.Request], fake.closeCalls)
+		}
+	}
+}
+
+func TestDeviceBuilderRejectsInvalidCapabilities(t *testing.T) {
+	tests := []struct {
+		builder  *DeviceBuilder
+		expected string
+	}{
+		{NewDeviceBuilder("/dev/uinput"), "device must declare at least one capability"},
+		{NewDeviceBuilder("/dev/uinput").Keys(keyCodeMax + 1), fmt.Sprintf("code %d of event type 1 is not in range (maximum is %d)", keyCodeMax+1, keyCodeMax)},
+		{NewDeviceBuilder("/dev/uinput").RelAxes(-1), fmt.Sprintf("code -1 of event type 2 is not in range (maximum is %d)", relMax)},
+		{NewDeviceBuilder("/dev/uinput").LEDs(ledMax + 1), fmt.Sprintf("code %d of event type 17 is not in range (maximum is %d)", ledMax+1, ledMax)},
+		{NewDeviceBuilder("/dev/uinput").AbsAxis(AbsX, 10, 0, 0, 0), "minimum 10 of absolute axis 0 is greater than its maximum 0"},
+	}
+
+	for _, test := range tests {
+		err := test.builder.validateCapabilities()
+		if err == nil || err.Error() != test.expected {
+			t.Fatalf("Expected: %s\nActual: %v", test.expected, err)
+		}
+	}
+}
+
+func TestDeviceBuilderCollectsCapabilities(t *testing.T) {
+	capabilities := NewDeviceBuilder("/dev/uinput").
+		Keys(KeyA, KeyB).
+		RelAxes(RelWheel).
+		AbsAxis(AbsX, 0, 100, 0, 0).
+		LEDs(LedCapsLock).
+		Sounds(0x01).
+		capabilities()
+
+	for _, c := range []struct {
+		evType uint16
+		code   int
+	}{{EvKey, KeyA}, {EvKey, KeyB}, {EvRel, RelWheel}, {EvAbs, AbsX}, {EvLed, LedCapsLock}, {EvSnd, 0x01}} {
+		if !capabilities[c.evType][c.code] {
+			t.Fatalf("Expected code %d of event type %d to be registered", c.code, c.evType)
+		}
+	}
+	if _, ok := capabilities[EvMsc]; ok {
+		t.Fatalf("Expected no misc events to be registered")
+	}
+}
+
+func TestGenericDeviceWritesEventsAsSingleFrame(t *testing.T) {
+	fake := NewFakeBackend()
+	dev, err := NewDeviceBuilder("/dev/uinput").
+		Name([]byte("Test Generic Device")).
+		RelAxes(RelX, RelY).
+		MiscEvents(0x04).
+		Create(WithFakeBackend(fake))
+	if err != nil {
+		t.Fatalf("Failed to create the generic device. Last error was: %s\n", err)
+	}
+	defer dev.Close()
+
+	err = dev.WriteEvents(Event{Type: EvMsc, Code: 0x04, Value: 42}, Event{Type: EvRel, Code: RelX, Value: -1})
+	if err != nil {
+		t.Fatalf("Failed to write events. Last error was: %s\n", err)
+	}
+	err = dev.WriteEvents(Event{Type: EvRel, Code: RelWheel, Value: 1})
+	if err == nil {
+		t.Fatalf("Expected events with unregistered codes to be rejected")
+	}
+	err = dev.WriteEvents(Event{Type: EvSyn, Code: SynReport})
+	if err == nil {
+		t.Fatalf("Expected sync events to be rejected")
+	}
+
+	expected := []Event{{Type: EvMsc, Code: 0x04, Value: 42}, {Type: EvRel, Code: RelX, Value: -1}, {Type: EvSyn, Code: SynReport}}
+	if actual := fake.Events(); !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, actual)
+	}
+}
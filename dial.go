@@ -3,8 +3,6 @@ package uinput
 import (
 	"fmt"
 	"io"
-	"os"
-	"syscall"
 )
 
 // A Dial is a device that will trigger rotation events.
@@ -13,17 +11,26 @@ type Dial interface {
 	// Turn will simulate a dial movement.
 	Turn(delta int32) error
 
+	// Info will return information about the device as seen by the kernel, such as its syspath and event node.
+	Info() (DeviceInfo, error)
+
+	// WriteEvents will write the given events followed by a sync event (SYN_REPORT) as a single frame. This allows to
+	// send events that are not covered by the other methods. All events need to refer to codes that have been
+	// registered for the device, sync events are not allowed.
+	WriteEvents(events ...Event) error
+
 	io.Closer
 }
 
 type vDial struct {
-	name       []byte
-	deviceFile *os.File
+	name []byte
+	dev  *uinputDevice
 }
 
 // CreateDial will create a new dial input device. A dial is a device that can trigger rotation events.
-func CreateDial(path string, name []byte) (Dial, error) {
-	err := validateDevicePath(path)
+// Dials use the product ID 0x081a by default, which tells them apart from mice (0x0816); use WithProduct to change it.
+func CreateDial(path string, name []byte, opts ...Option) (Dial, error) {
+	err := validateDevicePath(path, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -32,69 +39,74 @@ func CreateDial(path string, name []byte) (Dial, error) {
 		return nil, err
 	}
 
-	fd, err := createDial(path, name)
+	fd, err := createDial(path, name, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	return vDial{name: name, deviceFile: fd}, nil
+	return vDial{name: name, dev: newUinputDevice(fd, dialCapabilities())}, nil
 }
 
 // Turn will simulate a dial movement.
 func (vRel vDial) Turn(delta int32) error {
-	return sendDialEvent(vRel.deviceFile, delta)
+	return sendDialEvent(vRel.dev, delta)
+}
+
+// Info will return information about the device as seen by the kernel.
+func (vRel vDial) Info() (DeviceInfo, error) {
+	return vRel.dev.info()
+}
+
+// WriteEvents will write the given events as a single frame.
+func (vRel vDial) WriteEvents(events ...Event) error {
+	return vRel.dev.writeEvents(events)
 }
 
 // Close closes the device and releases the device.
 func (vRel vDial) Close() error {
-	return closeDevice(vRel.deviceFile)
+	return vRel.dev.close()
+}
+
+// dialCapabilities returns the codes registered by createDial.
+func dialCapabilities() capabilities {
+	caps := make(capabilities)
+	caps.add(EvRel, RelDial)
+	return caps
 }
 
-func createDial(path string, name []byte) (fd *os.File, err error) {
-	deviceFile, err := createDeviceFile(path)
+func createDial(path string, name []byte, opts []Option) (fd backend, err error) {
+	deviceFile, err := createDeviceFile(path, opts)
 	if err != nil {
-		return nil, fmt.Errorf("could not create dial input device: %v", err)
+		return nil, fmt.Errorf("could not create dial input device: %w", err)
 	}
 
-	err = registerDevice(deviceFile, uintptr(evRel))
+	err = registerDevice(deviceFile, uintptr(EvRel))
 	if err != nil {
-		deviceFile.Close()
-		return nil, fmt.Errorf("failed to register dial input device: %v", err)
+		// registerDevice closes the device file on failure
+		return nil, fmt.Errorf("failed to register dial input device: %w", err)
 	}
 
 	// register dial events
-	err = ioctl(deviceFile, uiSetRelBit, uintptr(relDial))
+	err = ioctl(deviceFile, uiSetRelBit, uintptr(RelDial))
 	if err != nil {
 		deviceFile.Close()
-		return nil, fmt.Errorf("failed to register dial events: %v", err)
+		return nil, fmt.Errorf("failed to register dial events: %w", err)
 	}
 
 	return createUsbDevice(deviceFile,
-		uinputUserDev{
-			Name: toUinputName(name),
-			ID: inputID{
+		deviceSetup{
+			name: name,
+			id: inputID{
 				Bustype: busUsb,
 				Vendor:  0x4711,
-				Product: 0x0816,
-				Version: 1}})
+				Product: 0x081a,
+				Version: 1}}, opts)
 }
 
-func sendDialEvent(deviceFile *os.File, delta int32) error {
-	iev := inputEvent{
-		Time:  syscall.Timeval{Sec: 0, Usec: 0},
-		Type:  evRel,
-		Code:  relDial,
-		Value: delta}
-
-	buf, err := inputEventToBuffer(iev)
+func sendDialEvent(dev *uinputDevice, delta int32) error {
+	err := dev.send(inputEvent{Type: EvRel, Code: RelDial, Value: delta})
 	if err != nil {
-		return fmt.Errorf("writing abs event failed: %v", err)
+		return fmt.Errorf("failed to write rel event to device file: %w", err)
 	}
-
-	_, err = deviceFile.Write(buf)
-	if err != nil {
-		return fmt.Errorf("failed to write rel event to device file: %v", err)
-	}
-
-	return syncEvents(deviceFile)
+	return nil
 }
@@ -1,10 +1,13 @@
 package uinput
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"testing"
+	"unsafe"
 )
 
 func TestDialWheel(t *testing.T) {
@@ -48,8 +51,8 @@ func TestDialCreationFailsOnEmptyPath(t *testing.T) {
 func TestDialCreationFailsOnNonExistentPathName(t *testing.T) {
 	path := "/some/bogus/path"
 	_, err := CreateDial(path, []byte("DialDevice"))
-	if !os.IsNotExist(err) {
-		t.Fatalf("Expected: os.IsNotExist error\nActual: %s", err)
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Expected: os.ErrNotExist error\nActual: %s", err)
 	}
 }
 
@@ -60,7 +63,7 @@ func TestDialCreationFailsOnWrongPathName(t *testing.T) {
 	}
 	defer file.Close()
 
-	expected := "failed to register dial input device: failed to close device: inappropriate ioctl for device"
+	expected := "failed to register dial input device: invalid file handle returned from ioctl: inappropriate ioctl for device (failed to close device: inappropriate ioctl for device)"
 	_, err = CreateDial(file.Name(), []byte("DialDevice"))
 	if err == nil || !(expected == err.Error()) {
 		t.Fatalf("Expected: %s\nActual: %s", expected, err)
@@ -75,3 +78,34 @@ func TestDialCreationFailsIfNameIsTooLong(t *testing.T) {
 		t.Fatalf("Expected: %s\nActual: %s", expected, err)
 	}
 }
+
+func TestDialAndMouseHaveDifferentDefaultIDs(t *testing.T) {
+	// the identity is at the start of the data passed to UI_DEV_SETUP
+	identity := func(fake *FakeBackend) []byte {
+		for _, request := range fake.Ioctls() {
+			if request.Request == uiDevSetup {
+				return request.Data[:unsafe.Sizeof(inputID{})]
+			}
+		}
+		t.Fatalf("Expected the device to be set up using UI_DEV_SETUP")
+		return nil
+	}
+
+	mouseBackend := NewFakeBackend()
+	mouse, err := CreateMouse("/dev/uinput", []byte("Test Mouse"), WithFakeBackend(mouseBackend))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual mouse. Last error was: %s\n", err)
+	}
+	defer mouse.Close()
+
+	dialBackend := NewFakeBackend()
+	dial, err := CreateDial("/dev/uinput", []byte("Test Dial"), WithFakeBackend(dialBackend))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual dial. Last error was: %s\n", err)
+	}
+	defer dial.Close()
+
+	if mouseID, dialID := identity(mouseBackend), identity(dialBackend); bytes.Equal(mouseID, dialID) {
+		t.Fatalf("Expected the default IDs of mouse and dial to differ, but both are %x", mouseID)
+	}
+}
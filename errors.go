@@ -0,0 +1,108 @@
+package uinput
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+var (
+	// ErrPermission is returned if the user lacks the permission to access the uinput device. Usually, this can be
+	// fixed by adding a udev rule that grants access to /dev/uinput to a group the user is a member of.
+	ErrPermission = errors.New("insufficient permissions to access the uinput device")
+
+	// ErrUinputMissing is returned if the uinput device does not exist, which usually means that the uinput kernel
+	// module has not been loaded (modprobe uinput).
+	ErrUinputMissing = errors.New("uinput device not available")
+
+	// ErrDeviceClosed is returned if events are sent to a device that has already been closed.
+	ErrDeviceClosed = errors.New("device has been closed")
+
+	// ErrKeyCodeOutOfRange is returned if a key or button code is not supported by the device.
+	ErrKeyCodeOutOfRange = errors.New("key code out of range")
+
+	// ErrAxisOutOfRange is returned if an axis or the value of an axis is not supported by the device.
+	ErrAxisOutOfRange = errors.New("axis out of range")
+
+	// ErrUnsupportedCharacter is returned if a character can not be typed using the layout of the keyboard.
+	ErrUnsupportedCharacter = errors.New("character not supported by the keyboard layout")
+)
+
+// IoctlError is returned if an ioctl request issued on the uinput device fails.
+type IoctlError struct {
+	// Request is the ioctl request number, for example UI_DEV_CREATE (0x5501).
+	Request uintptr
+	Errno   syscall.Errno
+}
+
+func (e *IoctlError) Error() string {
+	return e.Errno.Error()
+}
+
+// Unwrap returns the errno, so that errors.Is may be used to check for os.ErrPermission, for example.
+func (e *IoctlError) Unwrap() error {
+	return e.Errno
+}
+
+// Is reports whether the request failed due to missing permissions if target is ErrPermission.
+func (e *IoctlError) Is(target error) bool {
+	return target == ErrPermission && (e.Errno == syscall.EPERM || e.Errno == syscall.EACCES)
+}
+
+// sentinelError attaches one of the sentinel errors to an error without changing its message, which allows to match
+// the error using errors.Is while retaining the original cause.
+type sentinelError struct {
+	sentinel error
+	err      error
+}
+
+func (e *sentinelError) Error() string {
+	return e.err.Error()
+}
+
+func (e *sentinelError) Unwrap() error {
+	return e.err
+}
+
+func (e *sentinelError) Is(target error) bool {
+	return target == e.sentinel
+}
+
+func withSentinel(sentinel error, err error) error {
+	return &sentinelError{sentinel: sentinel, err: err}
+}
+
+// accessError attaches ErrPermission or ErrUinputMissing to errors that occur while accessing the uinput device, so
+// that callers are able to tell which of both applies.
+func accessError(err error) error {
+	var errno syscall.Errno
+	switch {
+	case errors.Is(err, os.ErrPermission):
+		return withSentinel(ErrPermission, err)
+	case errors.Is(err, os.ErrNotExist):
+		return withSentinel(ErrUinputMissing, err)
+	case errors.As(err, &errno) && (errno == syscall.ENODEV || errno == syscall.ENXIO):
+		// the device node exists, but there is no driver behind it
+		return withSentinel(ErrUinputMissing, err)
+	}
+	return err
+}
+
+// closedError attaches ErrDeviceClosed to errors that are caused by using a device file after it has been closed.
+func closedError(err error) error {
+	if errors.Is(err, os.ErrClosed) {
+		return withSentinel(ErrDeviceClosed, err)
+	}
+	return err
+}
+
+// keyCodeError returns an error for a key code that is not in range, which matches ErrKeyCodeOutOfRange.
+func keyCodeError(format string, a ...interface{}) error {
+	return withSentinel(ErrKeyCodeOutOfRange, fmt.Errorf(format, a...))
+}
+
+// axisError returns an error for an axis or axis value that is not in range, which matches ErrAxisOutOfRange.
+func axisError(format string, a ...interface{}) error {
+	return withSentinel(ErrAxisOutOfRange, fmt.Errorf(format, a...))
+}
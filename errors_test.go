@@ -0,0 +1,143 @@
+package uinput
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestMissingDevicePathMatchesErrUinputMissing(t *testing.T) {
+	err := validateDevicePath("/some/bogus/path", nil)
+	if !errors.Is(err, ErrUinputMissing) {
+		t.Fatalf("Expected error to match ErrUinputMissing, but got: %v", err)
+	}
+	if errors.Is(err, ErrPermission) {
+		t.Fatalf("Expected error not to match ErrPermission")
+	}
+}
+
+func TestAccessErrorTellsPermissionFromMissingModule(t *testing.T) {
+	tests := []struct {
+		errno    syscall.Errno
+		expected error
+	}{
+		{syscall.EACCES, ErrPermission},
+		{syscall.EPERM, ErrPermission},
+		{syscall.ENOENT, ErrUinputMissing},
+		{syscall.ENODEV, ErrUinputMissing},
+	}
+
+	for _, test := range tests {
+		cause := &os.PathError{Op: "open", Path: "/dev/uinput", Err: test.errno}
+		err := accessError(cause)
+		if !errors.Is(err, test.expected) {
+			t.Fatalf("Expected %v to match %v", test.errno, test.expected)
+		}
+		var pathErr *os.PathError
+		if !errors.As(err, &pathErr) || pathErr != cause {
+			t.Fatalf("Expected original cause to be retained for %v", test.errno)
+		}
+		if err.Error() != cause.Error() {
+			t.Fatalf("Expected: %s\nActual: %s", cause, err)
+		}
+	}
+}
+
+func TestIoctlErrorCarriesRequestAndErrno(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-ioctl-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	err = ioctl(&fileBackend{file: file}, uiDevCreate, uintptr(0))
+	var ioctlErr *IoctlError
+	if !errors.As(err, &ioctlErr) {
+		t.Fatalf("Expected an IoctlError, but got: %v", err)
+	}
+	if ioctlErr.Request != uiDevCreate || ioctlErr.Errno != syscall.ENOTTY {
+		t.Fatalf("Unexpected request %#x or errno %v", ioctlErr.Request, ioctlErr.Errno)
+	}
+	if err.Error() != "inappropriate ioctl for device" {
+		t.Fatalf("Unexpected error message: %s", err)
+	}
+}
+
+func TestFailedEventTypeRegistrationKeepsIoctlError(t *testing.T) {
+	fake := NewFakeBackend()
+	fake.failRequest(uiSetEvBit, EvKey, syscall.EINVAL)
+	_, err := CreateKeyboard("/dev/uinput", []byte("Test Keyboard"), WithFakeBackend(fake))
+
+	var ioctlErr *IoctlError
+	if !errors.As(err, &ioctlErr) {
+		t.Fatalf("Expected an IoctlError, but got: %v", err)
+	}
+	if ioctlErr.Request != uiSetEvBit || ioctlErr.Errno != syscall.EINVAL {
+		t.Fatalf("Unexpected request %#x or errno %v", ioctlErr.Request, ioctlErr.Errno)
+	}
+	if strings.Contains(err.Error(), "%!") {
+		t.Fatalf("Unexpected error message: %s", err)
+	}
+}
+
+func TestFailedReleaseIsReportedAlongWithIoctlError(t *testing.T) {
+	fake := NewFakeBackend()
+	fake.failRequest(uiSetEvBit, EvKey, syscall.EINVAL)
+	fake.failRequest(uiDevDestroy, 0, syscall.EBADF)
+	_, err := CreateKeyboard("/dev/uinput", []byte("Test Keyboard"), WithFakeBackend(fake))
+
+	if !errors.Is(err, syscall.EINVAL) {
+		t.Fatalf("Expected the error of the failed registration, but got: %v", err)
+	}
+	if !strings.Contains(err.Error(), syscall.EBADF.Error()) {
+		t.Fatalf("Expected the failed release to be reported, but got: %v", err)
+	}
+}
+
+func TestIoctlErrorMatchesErrPermission(t *testing.T) {
+	err := error(&IoctlError{Request: uiDevCreate, Errno: syscall.EACCES})
+	if !errors.Is(err, ErrPermission) || !errors.Is(err, os.ErrPermission) {
+		t.Fatalf("Expected EACCES to match ErrPermission and os.ErrPermission")
+	}
+	err = &IoctlError{Request: uiDevCreate, Errno: syscall.EINVAL}
+	if errors.Is(err, ErrPermission) {
+		t.Fatalf("Expected EINVAL not to match ErrPermission")
+	}
+}
+
+func TestSendingToClosedDeviceMatchesErrDeviceClosed(t *testing.T) {
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to open %s: %v", os.DevNull, err)
+	}
+	vk := &vKeyboard{dev: newUinputDevice(&fileBackend{file: devNull}, keyboardCapabilities(newDeviceOptions(nil)))}
+	_ = devNull.Close()
+
+	err = vk.KeyPress(KeyA)
+	if !errors.Is(err, ErrDeviceClosed) {
+		t.Fatalf("Expected error to match ErrDeviceClosed, but got: %v", err)
+	}
+}
+
+func TestRangeErrorsMatchSentinels(t *testing.T) {
+	vk := &vKeyboard{}
+	err := vk.KeyDown(-1)
+	if !errors.Is(err, ErrKeyCodeOutOfRange) {
+		t.Fatalf("Expected error to match ErrKeyCodeOutOfRange, but got: %v", err)
+	}
+
+	vm := vMouse{}
+	err = vm.MoveLeft(-1)
+	if !errors.Is(err, ErrAxisOutOfRange) {
+		t.Fatalf("Expected error to match ErrAxisOutOfRange, but got: %v", err)
+	}
+
+	err = NewDeviceBuilder("/dev/uinput").RelAxes(relMax + 1).validateCapabilities()
+	if !errors.Is(err, ErrAxisOutOfRange) {
+		t.Fatalf("Expected error to match ErrAxisOutOfRange, but got: %v", err)
+	}
+}
@@ -0,0 +1,263 @@
+/*
+Package evdev provides read access to the event device nodes (/dev/input/eventN) of input devices.
+It is mostly useful to verify which events the kernel actually emitted for a device created by the uinput package.
+
+In order to read the events of a uinput device, you will need to follow these steps:
+
+ 1. Open the event node using the syspath of the device
+    Example: dev, err := evdev.OpenSyspath(syspath)
+
+ 2. Optionally grab the device, so that no other application receives its events
+    Example: err = dev.Grab()
+
+ 3. Read the events
+    Example: ev, err := dev.ReadEvent()
+
+ 4. Close the device
+    Example: err = dev.Close()
+
+Note that reading from event nodes usually requires root privileges or membership in the "input" group.
+*/
+package evdev
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// Event is a single input event as reported by the kernel.
+type Event struct {
+	Time  time.Time
+	Type  uint16
+	Code  uint16
+	Value int32
+}
+
+// ID is the identity of a device.
+type ID struct {
+	BusType uint16
+	Vendor  uint16
+	Product uint16
+	Version uint16
+}
+
+// AbsInfo is the current value and the range of an absolute axis.
+type AbsInfo struct {
+	Value      int32
+	Minimum    int32
+	Maximum    int32
+	Fuzz       int32
+	Flat       int32
+	Resolution int32
+}
+
+// Device is an opened event node.
+type Device struct {
+	path string
+	file *os.File
+}
+
+var eventNodeName = regexp.MustCompile(`^event[0-9]+$`)
+
+// EventNode will return the path of the event node (/dev/input/eventN) that belongs to the input device with the
+// given syspath (/sys/devices/virtual/input/inputN).
+func EventNode(syspath string) (string, error) {
+	return eventNode(syspath, "/dev/input")
+}
+
+func eventNode(syspath string, devInputDir string) (string, error) {
+	syspath = strings.TrimRight(syspath, "\x00")
+	entries, err := ioutil.ReadDir(syspath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read syspath: %w", err)
+	}
+	for _, entry := range entries {
+		if eventNodeName.MatchString(entry.Name()) {
+			return filepath.Join(devInputDir, entry.Name()), nil
+		}
+	}
+	return "", fmt.Errorf("no event node found for %s", syspath)
+}
+
+// Open will open the event node at the given path.
+func Open(path string) (*Device, error) {
+	file, err := os.OpenFile(path, syscall.O_RDONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &Device{path: path, file: file}, nil
+}
+
+// OpenSyspath will open the event node that belongs to the input device with the given syspath.
+func OpenSyspath(syspath string) (*Device, error) {
+	path, err := EventNode(syspath)
+	if err != nil {
+		return nil, err
+	}
+	return Open(path)
+}
+
+// Path will return the path of the event node.
+func (d *Device) Path() string {
+	return d.path
+}
+
+// ReadEvent will block until the next event is available. Once the device has been closed, an error is returned.
+func (d *Device) ReadEvent() (Event, error) {
+	buf := make([]byte, unsafe.Sizeof(inputEvent{}))
+	_, err := io.ReadFull(d.file, buf)
+	if err != nil {
+		return Event{}, err
+	}
+	return decodeEvent(buf)
+}
+
+// Grab will grab the device (EVIOCGRAB), so that its events are no longer delivered to any other application.
+func (d *Device) Grab() error {
+	return d.ioctl(eviocGrab, 1)
+}
+
+// Release will release a grabbed device.
+func (d *Device) Release() error {
+	return d.ioctl(eviocGrab, 0)
+}
+
+// Name will return the name of the device (EVIOCGNAME).
+func (d *Device) Name() (string, error) {
+	buf := make([]byte, maxNameSize)
+	err := d.ioctlPtr(eviocGName(len(buf)), unsafe.Pointer(&buf[0]))
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch device name: %w", err)
+	}
+	return string(bytes.TrimRight(buf, "\x00")), nil
+}
+
+// ID will return the identity of the device (EVIOCGID).
+func (d *Device) ID() (ID, error) {
+	var id inputID
+	err := d.ioctlPtr(eviocGID, unsafe.Pointer(&id))
+	if err != nil {
+		return ID{}, fmt.Errorf("failed to fetch device id: %w", err)
+	}
+	return ID{BusType: id.Bustype, Vendor: id.Vendor, Product: id.Product, Version: id.Version}, nil
+}
+
+// Properties will return all input properties of the device (EVIOCGPROP).
+func (d *Device) Properties() ([]uint16, error) {
+	bits := make([]byte, propBitmapSize)
+	err := d.ioctlPtr(eviocGProp(len(bits)), unsafe.Pointer(&bits[0]))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch device properties: %w", err)
+	}
+	return bitsToCodes(bits), nil
+}
+
+// AbsInfo will return the current value and the range of the given absolute axis (EVIOCGABS).
+func (d *Device) AbsInfo(axis uint16) (AbsInfo, error) {
+	var info inputAbsInfo
+	err := d.ioctlPtr(eviocGAbs(axis), unsafe.Pointer(&info))
+	if err != nil {
+		return AbsInfo{}, fmt.Errorf("failed to fetch range of absolute axis %d: %w", axis, err)
+	}
+	return AbsInfo(info), nil
+}
+
+// EventTypes will return all event types supported by the device (EVIOCGBIT with event type 0).
+func (d *Device) EventTypes() ([]uint16, error) {
+	return d.Codes(0)
+}
+
+// Codes will return all codes of the given event type supported by the device (EVIOCGBIT).
+func (d *Device) Codes(evType uint16) ([]uint16, error) {
+	bits := make([]byte, bitmapSize)
+	err := d.ioctlPtr(eviocGBit(evType, len(bits)), unsafe.Pointer(&bits[0]))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch codes of event type %d: %w", evType, err)
+	}
+	return bitsToCodes(bits), nil
+}
+
+// PressedKeys will return the keys and buttons that are currently held down (EVIOCGKEY).
+func (d *Device) PressedKeys() ([]uint16, error) {
+	bits := make([]byte, bitmapSize)
+	err := d.ioctlPtr(eviocGKey(len(bits)), unsafe.Pointer(&bits[0]))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch key state: %w", err)
+	}
+	return bitsToCodes(bits), nil
+}
+
+// Close will close the event node. A pending ReadEvent will return an error.
+func (d *Device) Close() error {
+	return d.file.Close()
+}
+
+func (d *Device) ioctl(cmd, arg uintptr) error {
+	return d.control(func(fd uintptr) syscall.Errno {
+		_, _, errorCode := syscall.Syscall(syscall.SYS_IOCTL, fd, cmd, arg)
+		return errorCode
+	})
+}
+
+func (d *Device) ioctlPtr(cmd uintptr, ptr unsafe.Pointer) error {
+	return d.control(func(fd uintptr) syscall.Errno {
+		_, _, errorCode := syscall.Syscall(syscall.SYS_IOCTL, fd, cmd, uintptr(ptr))
+		return errorCode
+	})
+}
+
+// control uses the raw connection, since calling Fd() would put the file into blocking mode, which would prevent a
+// pending read from being interrupted by Close.
+func (d *Device) control(call func(fd uintptr) syscall.Errno) error {
+	conn, err := d.file.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var errorCode syscall.Errno
+	err = conn.Control(func(fd uintptr) {
+		errorCode = call(fd)
+	})
+	if err != nil {
+		return err
+	}
+	if errorCode != 0 {
+		return errorCode
+	}
+	return nil
+}
+
+func decodeEvent(buf []byte) (Event, error) {
+	var iev inputEvent
+	err := binary.Read(bytes.NewReader(buf), binary.LittleEndian, &iev)
+	if err != nil {
+		return Event{}, errors.New("failed to decode input event")
+	}
+	return Event{
+		Time:  time.Unix(int64(iev.Time.Sec), int64(iev.Time.Usec)*int64(time.Microsecond)),
+		Type:  iev.Type,
+		Code:  iev.Code,
+		Value: iev.Value}, nil
+}
+
+func bitsToCodes(bits []byte) []uint16 {
+	var codes []uint16
+	for i, b := range bits {
+		for bit := uint(0); bit < 8; bit++ {
+			if b&(1<<bit) != 0 {
+				codes = append(codes, uint16(i*8)+uint16(bit))
+			}
+		}
+	}
+	return codes
+}
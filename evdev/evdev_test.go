@@ -0,0 +1,109 @@
+package evdev
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestEventNodeIsResolvedFromSyspath(t *testing.T) {
+	syspath, err := ioutil.TempDir(os.TempDir(), "evdev-syspath-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(syspath)
+
+	for _, dir := range []string{"capabilities", "id", "js0", "event12", "mouse3"} {
+		err = os.Mkdir(filepath.Join(syspath, dir), 0755)
+		if err != nil {
+			t.Fatalf("Failed to setup test. Unable to create dir: %v", err)
+		}
+	}
+
+	// the syspath returned by the uinput package may be padded with null bytes
+	node, err := eventNode(syspath+"\x00\x00", "/dev/input")
+	if err != nil {
+		t.Fatalf("Failed to resolve event node. Last error was: %s\n", err)
+	}
+	if node != "/dev/input/event12" {
+		t.Fatalf("Expected: /dev/input/event12\nActual: %s", node)
+	}
+}
+
+func TestEventNodeResolutionFailsWithoutEventHandler(t *testing.T) {
+	syspath, err := ioutil.TempDir(os.TempDir(), "evdev-syspath-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(syspath)
+
+	_, err = eventNode(syspath, "/dev/input")
+	if err == nil {
+		t.Fatalf("Expected resolution to fail, since there is no event node")
+	}
+}
+
+func TestEventNodeResolutionFailsOnBogusSyspath(t *testing.T) {
+	_, err := EventNode("/some/bogus/path")
+	if err == nil {
+		t.Fatalf("Expected resolution to fail on non-existent syspath")
+	}
+}
+
+func TestEventDecoding(t *testing.T) {
+	buf := new(bytes.Buffer)
+	err := binary.Write(buf, binary.LittleEndian, inputEvent{
+		Time:  syscall.Timeval{Sec: 1500000000, Usec: 250},
+		Type:  0x01,
+		Code:  30,
+		Value: 1})
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to encode event: %v", err)
+	}
+
+	ev, err := decodeEvent(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Failed to decode event. Last error was: %s\n", err)
+	}
+	expected := Event{Time: time.Unix(1500000000, 250000), Type: 0x01, Code: 30, Value: 1}
+	if !ev.Time.Equal(expected.Time) || ev.Type != expected.Type || ev.Code != expected.Code || ev.Value != expected.Value {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, ev)
+	}
+}
+
+func TestBitsToCodes(t *testing.T) {
+	codes := bitsToCodes([]byte{0x05, 0x00, 0x80})
+	expected := []uint16{0, 2, 23}
+	if len(codes) != len(expected) {
+		t.Fatalf("Expected: %v\nActual: %v", expected, codes)
+	}
+	for i := range codes {
+		if codes[i] != expected[i] {
+			t.Fatalf("Expected: %v\nActual: %v", expected, codes)
+		}
+	}
+}
+
+func TestIoctlRequestNumbers(t *testing.T) {
+	for _, test := range []struct {
+		actual   uintptr
+		expected uintptr
+	}{
+		{eviocGName(256), 0x81004506},
+		{eviocGKey(96), 0x80604518},
+		{eviocGBit(0, 96), 0x80604520},
+		{eviocGBit(0x03, 8), 0x80084523},
+		{eviocGProp(4), 0x80044509},
+		{eviocGAbs(0x00), 0x80184540},
+		{eviocGAbs(0x35), 0x80184575},
+	} {
+		if test.actual != test.expected {
+			t.Fatalf("Expected: %#x\nActual: %#x", test.expected, test.actual)
+		}
+	}
+}
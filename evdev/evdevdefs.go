@@ -0,0 +1,66 @@
+package evdev
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// types needed from input.h
+const (
+	// large enough for the name of any uinput device
+	maxNameSize = 256
+	// large enough for the largest bitmap (KEY_MAX + 1 bits)
+	bitmapSize = (0x2ff + 1) / 8
+
+	// large enough for all input properties (INPUT_PROP_MAX + 1 bits)
+	propBitmapSize = (0x1f + 1) / 8
+
+	eviocGrab = 0x40044590
+	eviocGID  = 0x80084502
+
+	iocRead = 0x80000000
+)
+
+func eviocGName(length int) uintptr {
+	return iocRead | uintptr(length)<<16 | 0x4506
+}
+
+func eviocGKey(length int) uintptr {
+	return iocRead | uintptr(length)<<16 | 0x4518
+}
+
+func eviocGProp(length int) uintptr {
+	return iocRead | uintptr(length)<<16 | 0x4509
+}
+
+func eviocGAbs(axis uint16) uintptr {
+	return iocRead | unsafe.Sizeof(inputAbsInfo{})<<16 | 0x4540 | uintptr(axis)
+}
+
+func eviocGBit(evType uint16, length int) uintptr {
+	return iocRead | uintptr(length)<<16 | 0x4500 | uintptr(0x20+evType)
+}
+
+// translated to go from input.h
+type inputEvent struct {
+	Time  syscall.Timeval
+	Type  uint16
+	Code  uint16
+	Value int32
+}
+
+type inputID struct {
+	Bustype uint16
+	Vendor  uint16
+	Product uint16
+	Version uint16
+}
+
+type inputAbsInfo struct {
+	Value      int32
+	Minimum    int32
+	Maximum    int32
+	Fuzz       int32
+	Flat       int32
+	Resolution int32
+}
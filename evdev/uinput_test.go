@@ -0,0 +1,130 @@
+package evdev_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/bendahl/uinput"
+	"github.com/bendahl/uinput/evdev"
+)
+
+func TestKeyboardEventsCanBeReadBack(t *testing.T) {
+	vk, err := uinput.CreateKeyboard("/dev/uinput", []byte("Test Evdev Keyboard"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	defer vk.Close()
+
+	dev := openDevice(t, vk)
+	defer dev.Close()
+
+	name, err := dev.Name()
+	if err != nil {
+		t.Fatalf("Failed to fetch device name. Last error was: %s\n", err)
+	}
+	if name != "Test Evdev Keyboard" {
+		t.Fatalf("Expected: Test Evdev Keyboard\nActual: %s", name)
+	}
+
+	err = vk.KeyPress(uinput.KeyA)
+	if err != nil {
+		t.Fatalf("Failed to send key press. Last error was: %s\n", err)
+	}
+
+	for _, expected := range []evdev.Event{
+		{Type: uinput.EvKey, Code: uinput.KeyA, Value: 1},
+		{Type: uinput.EvSyn},
+		{Type: uinput.EvKey, Code: uinput.KeyA, Value: 0},
+		{Type: uinput.EvSyn},
+	} {
+		ev := nextEvent(t, dev)
+		if ev.Type != expected.Type || ev.Code != expected.Code || ev.Value != expected.Value {
+			t.Fatalf("Expected: %+v\nActual: %+v", expected, ev)
+		}
+	}
+}
+
+func TestKeyboardCapabilitiesAndKeyState(t *testing.T) {
+	vk, err := uinput.CreateKeyboard("/dev/uinput", []byte("Test Evdev Keyboard"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	defer vk.Close()
+
+	dev := openDevice(t, vk)
+	defer dev.Close()
+
+	types, err := dev.EventTypes()
+	if err != nil {
+		t.Fatalf("Failed to fetch event types. Last error was: %s\n", err)
+	}
+	if !contains(types, uinput.EvKey) {
+		t.Fatalf("Expected event types %v to contain EV_KEY", types)
+	}
+
+	keys, err := dev.Codes(uinput.EvKey)
+	if err != nil {
+		t.Fatalf("Failed to fetch key codes. Last error was: %s\n", err)
+	}
+	if !contains(keys, uinput.KeyA) || !contains(keys, uinput.KeyMicmute) {
+		t.Fatalf("Expected key codes to contain KeyA and KeyMicmute")
+	}
+
+	err = vk.KeyDown(uinput.KeyLeftshift)
+	if err != nil {
+		t.Fatalf("Failed to send key down event. Last error was: %s\n", err)
+	}
+	defer vk.KeyUp(uinput.KeyLeftshift)
+
+	// wait for the key event to arrive
+	nextEvent(t, dev)
+	pressed, err := dev.PressedKeys()
+	if err != nil {
+		t.Fatalf("Failed to fetch key state. Last error was: %s\n", err)
+	}
+	if !contains(pressed, uinput.KeyLeftshift) {
+		t.Fatalf("Expected %v to contain KeyLeftshift", pressed)
+	}
+}
+
+func openDevice(t *testing.T, vk uinput.Keyboard) *evdev.Device {
+	syspath, err := vk.FetchSyspath()
+	if err != nil {
+		t.Fatalf("Failed to fetch syspath. Last error was: %s\n", err)
+	}
+	dev, err := evdev.OpenSyspath(syspath)
+	if os.IsPermission(err) {
+		t.Skipf("Insufficient permissions to read event node: %s", err)
+	}
+	if err != nil {
+		t.Fatalf("Failed to open event node. Last error was: %s\n", err)
+	}
+
+	err = dev.Grab()
+	if err != nil {
+		t.Fatalf("Failed to grab device. Last error was: %s\n", err)
+	}
+	return dev
+}
+
+// nextEvent skips led events, since the system may update the keyboard leds at any time.
+func nextEvent(t *testing.T, dev *evdev.Device) evdev.Event {
+	for {
+		ev, err := dev.ReadEvent()
+		if err != nil {
+			t.Fatalf("Failed to read event. Last error was: %s\n", err)
+		}
+		if ev.Type != uinput.EvLed {
+			return ev
+		}
+	}
+}
+
+func contains(codes []uint16, code uint16) bool {
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,241 @@
+package evemu
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bendahl/uinput"
+)
+
+// Description describes a device as printed by evemu-describe.
+type Description struct {
+	Name    string
+	BusType uint16
+	Vendor  uint16
+	Product uint16
+	Version uint16
+
+	// Properties lists the input properties (INPUT_PROP_*) of the device.
+	Properties []int
+
+	// Codes lists the supported codes per event type, for example the supported keys for EV_KEY.
+	Codes map[uint16][]int
+
+	// AbsAxes holds the range of each absolute axis.
+	AbsAxes []AbsInfo
+}
+
+// AbsInfo is the range of an absolute axis.
+type AbsInfo struct {
+	Code       int
+	Min        int32
+	Max        int32
+	Fuzz       int32
+	Flat       int32
+	Resolution int32
+}
+
+// ReadDescription will read a device description, i.e. the name (N:), identity (I:), properties (P:), supported
+// codes (B:) and absolute axes (A:). All other lines are ignored, so a complete recording may be passed as well.
+func ReadDescription(r io.Reader) (*Description, error) {
+	d := &Description{Codes: make(map[uint16][]int)}
+	var properties []byte
+	bitmasks := make(map[uint16][]byte)
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if len(text) < 2 || text[1] != ':' {
+			continue
+		}
+		value := strings.TrimSpace(text[2:])
+		var err error
+		switch text[0] {
+		case 'N':
+			d.Name = value
+		case 'I':
+			err = d.parseID(value)
+		case 'P':
+			var mask []byte
+			mask, err = parseBytes(strings.Fields(value))
+			properties = append(properties, mask...)
+		case 'B':
+			err = parseBitmask(value, bitmasks)
+		case 'A':
+			err = d.parseAbsInfo(value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid description in line %d: %w", line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read description: %w", err)
+	}
+	if d.Name == "" {
+		return nil, errors.New("description does not contain a device name")
+	}
+
+	d.Properties = bits(properties)
+	for evType, mask := range bitmasks {
+		if codes := bits(mask); len(codes) > 0 {
+			d.Codes[evType] = codes
+		}
+	}
+	return d, nil
+}
+
+func (d *Description) parseID(value string) error {
+	fields := strings.Fields(value)
+	if len(fields) != 4 {
+		return fmt.Errorf("expected 4 fields, but got %d", len(fields))
+	}
+	id := make([]uint16, len(fields))
+	for i, field := range fields {
+		n, err := strconv.ParseUint(field, 16, 16)
+		if err != nil {
+			return fmt.Errorf("invalid id: %w", err)
+		}
+		id[i] = uint16(n)
+	}
+	d.BusType, d.Vendor, d.Product, d.Version = id[0], id[1], id[2], id[3]
+	return nil
+}
+
+// parseAbsInfo parses the code (hex), minimum, maximum, fuzz, flat and resolution of an absolute axis. The resolution
+// is missing in descriptions created by older versions of evemu.
+func (d *Description) parseAbsInfo(value string) error {
+	fields := strings.Fields(value)
+	if len(fields) != 5 && len(fields) != 6 {
+		return fmt.Errorf("expected 5 or 6 fields, but got %d", len(fields))
+	}
+	code, err := strconv.ParseUint(fields[0], 16, 16)
+	if err != nil {
+		return fmt.Errorf("invalid axis: %w", err)
+	}
+	var info [5]int32
+	for i, field := range fields[1:] {
+		n, err := strconv.ParseInt(field, 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid range of axis %d: %w", code, err)
+		}
+		info[i] = int32(n)
+	}
+	d.AbsAxes = append(d.AbsAxes, AbsInfo{
+		Code:       int(code),
+		Min:        info[0],
+		Max:        info[1],
+		Fuzz:       info[2],
+		Flat:       info[3],
+		Resolution: info[4],
+	})
+	return nil
+}
+
+// parseBitmask parses the event type followed by a part of its bitmask. Bitmasks that don't fit into a single line
+// are continued in the next line with the same event type.
+func parseBitmask(value string, bitmasks map[uint16][]byte) error {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return errors.New("missing event type")
+	}
+	evType, err := strconv.ParseUint(fields[0], 16, 16)
+	if err != nil {
+		return fmt.Errorf("invalid event type: %w", err)
+	}
+	mask, err := parseBytes(fields[1:])
+	if err != nil {
+		return err
+	}
+	bitmasks[uint16(evType)] = append(bitmasks[uint16(evType)], mask...)
+	return nil
+}
+
+func parseBytes(fields []string) ([]byte, error) {
+	mask := make([]byte, len(fields))
+	for i, field := range fields {
+		n, err := strconv.ParseUint(field, 16, 8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bitmask: %w", err)
+		}
+		mask[i] = byte(n)
+	}
+	return mask, nil
+}
+
+// bits returns the numbers of all bits that are set in the given bitmask, least significant byte first.
+func bits(mask []byte) []int {
+	var set []int
+	for i, b := range mask {
+		for bit := 0; bit < 8; bit++ {
+			if b&(1<<bit) != 0 {
+				set = append(set, i*8+bit)
+			}
+		}
+	}
+	return set
+}
+
+// Create will create a device that matches the description using the given uinput device path. The identity and
+// properties of the description are applied before the given options, so they may be overridden. Only the event types
+// supported by uinput.DeviceBuilder are registered; autorepeat (EV_REP) and force feedback (EV_FF) are ignored.
+func (d *Description) Create(path string, opts ...uinput.Option) (uinput.Device, error) {
+	builder := uinput.NewDeviceBuilder(path).
+		Name([]byte(d.Name)).
+		Keys(d.Codes[uinput.EvKey]...).
+		RelAxes(d.Codes[uinput.EvRel]...).
+		MiscEvents(d.Codes[uinput.EvMsc]...).
+		Switches(d.Codes[uinput.EvSw]...).
+		LEDs(d.Codes[uinput.EvLed]...).
+		Sounds(d.Codes[uinput.EvSnd]...)
+
+	// axes that are part of the bitmask, but lack a range, are registered with an empty range
+	ranges := make(map[int]AbsInfo)
+	for _, axis := range d.AbsAxes {
+		ranges[axis.Code] = axis
+	}
+	for _, code := range d.Codes[uinput.EvAbs] {
+		if _, ok := ranges[code]; !ok {
+			ranges[code] = AbsInfo{Code: code}
+		}
+	}
+	codes := make([]int, 0, len(ranges))
+	for code := range ranges {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		axis := ranges[code]
+		builder.AbsAxis(code, axis.Min, axis.Max, axis.Fuzz, axis.Flat)
+		if axis.Resolution != 0 {
+			builder.AbsResolution(code, axis.Resolution)
+		}
+	}
+
+	properties := make([]uinput.Property, len(d.Properties))
+	for i, prop := range d.Properties {
+		properties[i] = uinput.Property(prop)
+	}
+	options := []uinput.Option{
+		uinput.WithBusType(uinput.BusType(d.BusType)),
+		uinput.WithVendor(d.Vendor),
+		uinput.WithProduct(d.Product),
+		uinput.WithVersion(d.Version),
+		uinput.WithProperties(properties...),
+	}
+	return builder.Create(append(options, opts...)...)
+}
+
+// CreateFromDescription will read a device description as printed by evemu-describe and create a matching device
+// using the given uinput device path. This allows to emulate specific hardware. See Description.Create for details.
+func CreateFromDescription(path string, r io.Reader, opts ...uinput.Option) (uinput.Device, error) {
+	d, err := ReadDescription(r)
+	if err != nil {
+		return nil, err
+	}
+	return d.Create(path, opts...)
+}
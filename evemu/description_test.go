@@ -0,0 +1,159 @@
+package evemu
+
+import (
+	"encoding/binary"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bendahl/uinput"
+)
+
+func readTouchpadDescription(t *testing.T) *Description {
+	file, err := os.Open("testdata/touchpad.desc")
+	if err != nil {
+		t.Fatalf("Failed to open description: %v", err)
+	}
+	defer file.Close()
+
+	d, err := ReadDescription(file)
+	if err != nil {
+		t.Fatalf("Failed to read description: %v", err)
+	}
+	return d
+}
+
+func TestReadDescription(t *testing.T) {
+	d := readTouchpadDescription(t)
+
+	if d.Name != "SynPS/2 Synaptics TouchPad" {
+		t.Fatalf("Unexpected name: %q", d.Name)
+	}
+	if d.BusType != 0x11 || d.Vendor != 0x02 || d.Product != 0x07 || d.Version != 0x01b1 {
+		t.Fatalf("Unexpected identity: %04x %04x %04x %04x", d.BusType, d.Vendor, d.Product, d.Version)
+	}
+	if !reflect.DeepEqual([]int{0, 2}, d.Properties) {
+		t.Fatalf("Unexpected properties: %v", d.Properties)
+	}
+	if expected := []int{0x110, 0x145, 0x14a, 0x14d}; !reflect.DeepEqual(expected, d.Codes[uinput.EvKey]) {
+		t.Fatalf("Expected keys %v, but got %v", expected, d.Codes[uinput.EvKey])
+	}
+	if expected := []int{0x00, 0x01, 0x18, 0x2f, 0x35, 0x36, 0x39}; !reflect.DeepEqual(expected, d.Codes[uinput.EvAbs]) {
+		t.Fatalf("Expected absolute axes %v, but got %v", expected, d.Codes[uinput.EvAbs])
+	}
+	if _, ok := d.Codes[uinput.EvRel]; ok {
+		t.Fatalf("Expected empty bitmasks to be omitted, but got relative axes %v", d.Codes[uinput.EvRel])
+	}
+	if len(d.AbsAxes) != 7 {
+		t.Fatalf("Expected 7 absolute axes, but got %d", len(d.AbsAxes))
+	}
+	if expected := (AbsInfo{Code: 0x35, Min: 1266, Max: 5676, Resolution: 42}); d.AbsAxes[4] != expected {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, d.AbsAxes[4])
+	}
+}
+
+func TestReadDescriptionWithoutResolution(t *testing.T) {
+	d, err := ReadDescription(strings.NewReader("N: Old Device\nA: 00 0 255 4 8\n"))
+	if err != nil {
+		t.Fatalf("Failed to read description: %v", err)
+	}
+	if expected := (AbsInfo{Code: 0, Max: 255, Fuzz: 4, Flat: 8}); d.AbsAxes[0] != expected {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, d.AbsAxes[0])
+	}
+}
+
+func TestReadDescriptionRejectsInvalidDescriptions(t *testing.T) {
+	for _, desc := range []string{
+		"I: 0003 046d c52b 0111\n",
+		"N: Device\nI: 0003 046d c52b\n",
+		"N: Device\nB: 01 zz\n",
+		"N: Device\nA: 00 0 255\n",
+	} {
+		_, err := ReadDescription(strings.NewReader(desc))
+		if err == nil {
+			t.Fatalf("Expected %q to be rejected", desc)
+		}
+	}
+}
+
+func TestCreateFromDescription(t *testing.T) {
+	file, err := os.Open("testdata/touchpad.desc")
+	if err != nil {
+		t.Fatalf("Failed to open description: %v", err)
+	}
+	defer file.Close()
+
+	fake := uinput.NewFakeBackend()
+	dev, err := CreateFromDescription("/dev/uinput", file, uinput.WithFakeBackend(fake))
+	if err != nil {
+		t.Fatalf("Failed to create the device. Last error was: %s\n", err)
+	}
+	defer dev.Close()
+
+	var keys, props []uintptr
+	var absSetups [][]byte
+	var id []uint16
+	for _, request := range fake.Ioctls() {
+		switch request.Name {
+		case "UI_SET_KEYBIT":
+			keys = append(keys, request.Arg)
+		case "UI_SET_PROPBIT":
+			props = append(props, request.Arg)
+		case "UI_ABS_SETUP":
+			absSetups = append(absSetups, request.Data)
+		case "UI_DEV_SETUP":
+			for i := 0; i < 4; i++ {
+				id = append(id, binary.LittleEndian.Uint16(request.Data[i*2:]))
+			}
+		}
+	}
+
+	if expected := []uintptr{0x110, 0x145, 0x14a, 0x14d}; !reflect.DeepEqual(expected, keys) {
+		t.Fatalf("Expected keys %v to be registered, but got %v", expected, keys)
+	}
+	if expected := []uintptr{0, 2}; !reflect.DeepEqual(expected, props) {
+		t.Fatalf("Expected properties %v to be registered, but got %v", expected, props)
+	}
+	if expected := []uint16{0x11, 0x02, 0x07, 0x01b1}; !reflect.DeepEqual(expected, id) {
+		t.Fatalf("Expected identity %04x, but got %04x", expected, id)
+	}
+	if len(absSetups) != 7 {
+		t.Fatalf("Expected 7 absolute axes to be set up, but got %d", len(absSetups))
+	}
+
+	// struct uinput_abs_setup: code, padding, value, minimum, maximum, fuzz, flat, resolution
+	setup := absSetups[4]
+	code := binary.LittleEndian.Uint16(setup[0:])
+	min := int32(binary.LittleEndian.Uint32(setup[8:]))
+	max := int32(binary.LittleEndian.Uint32(setup[12:]))
+	resolution := int32(binary.LittleEndian.Uint32(setup[24:]))
+	if code != 0x35 || min != 1266 || max != 5676 || resolution != 42 {
+		t.Fatalf("Unexpected setup of ABS_MT_POSITION_X: code %#x, range %d-%d, resolution %d", code, min, max, resolution)
+	}
+
+	err = dev.AbsMove(0x35, 2000)
+	if err != nil {
+		t.Fatalf("Failed to move along a described axis. Last error was: %s\n", err)
+	}
+}
+
+func TestCreateFromDescriptionAppliesOptionsLast(t *testing.T) {
+	fake := uinput.NewFakeBackend()
+	dev, err := CreateFromDescription("/dev/uinput", strings.NewReader("N: Device\nI: 0003 046d c52b 0111\nB: 01 00 00 00 00 01\n"),
+		uinput.WithFakeBackend(fake), uinput.WithVendor(0x1234))
+	if err != nil {
+		t.Fatalf("Failed to create the device. Last error was: %s\n", err)
+	}
+	defer dev.Close()
+
+	for _, request := range fake.Ioctls() {
+		if request.Name == "UI_DEV_SETUP" {
+			if vendor := binary.LittleEndian.Uint16(request.Data[2:]); vendor != 0x1234 {
+				t.Fatalf("Expected vendor to be overridden with 0x1234, but got %#04x", vendor)
+			}
+			return
+		}
+	}
+	t.Fatalf("Expected the device to be set up using UI_DEV_SETUP")
+}
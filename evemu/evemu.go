@@ -0,0 +1,128 @@
+/*
+Package evemu records and replays input events in the text format used by evemu (https://www.freedesktop.org/wiki/Evemu/).
+This allows to replay recordings made with evemu-record using a virtual device created by the uinput package, without
+the need to install evemu-tools.
+
+An event is stored as a single line, holding the time relative to the first event, the type, the code and the value
+of the event:
+
+	E: 0.008001 0001 001e 0001
+
+All other lines (comments starting with # and the device description) are ignored when reading events.
+
+In order to replay a recording, you will need to follow these steps:
+
+ 1. Read the recorded events
+    Example: events, err := evemu.ReadEvents(file)
+
+ 2. Create a device with matching capabilities
+    Example: dev, err := uinput.NewDeviceBuilder("/dev/uinput").Name([]byte("Replay")).Keys(uinput.KeyA).Create()
+
+ 3. Replay the events
+    Example: err = evemu.Play(ctx, dev, events, evemu.PlayOptions{Speed: 2})
+*/
+package evemu
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bendahl/uinput"
+)
+
+// Event is a single recorded event. The time is relative to the first event of the recording.
+type Event struct {
+	Time  time.Duration
+	Type  uint16
+	Code  uint16
+	Value int32
+}
+
+// ReadEvents will read all events (E: lines) from the given recording.
+func ReadEvents(r io.Reader) ([]Event, error) {
+	var events []Event
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(text, "E:") {
+			continue
+		}
+		ev, err := parseEvent(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid event in line %d: %w", line, err)
+		}
+		events = append(events, ev)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read events: %w", err)
+	}
+	return events, nil
+}
+
+func parseEvent(line string) (Event, error) {
+	// strip the trailing comment that evemu-record adds to each event
+	if i := strings.Index(line, "#"); i >= 0 {
+		line = line[:i]
+	}
+	fields := strings.Fields(strings.TrimPrefix(line, "E:"))
+	if len(fields) != 4 {
+		return Event{}, fmt.Errorf("expected 4 fields, but got %d", len(fields))
+	}
+
+	timestamp, err := parseTime(fields[0])
+	if err != nil {
+		return Event{}, err
+	}
+	evType, err := strconv.ParseUint(fields[1], 16, 16)
+	if err != nil {
+		return Event{}, fmt.Errorf("invalid type: %w", err)
+	}
+	code, err := strconv.ParseUint(fields[2], 16, 16)
+	if err != nil {
+		return Event{}, fmt.Errorf("invalid code: %w", err)
+	}
+	value, err := strconv.ParseInt(fields[3], 10, 32)
+	if err != nil {
+		return Event{}, fmt.Errorf("invalid value: %w", err)
+	}
+	return Event{Time: timestamp, Type: uint16(evType), Code: uint16(code), Value: int32(value)}, nil
+}
+
+// parseTime parses a timestamp given in seconds with microsecond precision (sec.usec).
+func parseTime(s string) (time.Duration, error) {
+	parts := strings.SplitN(s, ".", 2)
+	sec, err := strconv.ParseUint(parts[0], 10, 63)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time: %w", err)
+	}
+	var usec uint64
+	if len(parts) == 2 {
+		usec, err = strconv.ParseUint(parts[1], 10, 32)
+		if err != nil || len(parts[1]) != 6 {
+			return 0, fmt.Errorf("invalid time: %s", s)
+		}
+	}
+	return time.Duration(sec)*time.Second + time.Duration(usec)*time.Microsecond, nil
+}
+
+// WriteEvent will write a single event in evemu format. Sync events are followed by a comment that marks the end of
+// a frame along with the time elapsed since the previous frame, just like evemu-record does.
+func WriteEvent(w io.Writer, ev Event, sincePreviousFrame time.Duration) error {
+	usec := ev.Time / time.Microsecond
+	_, err := fmt.Fprintf(w, "E: %d.%06d %04x %04x %04d", usec/1000000, usec%1000000, ev.Type, ev.Code, ev.Value)
+	if err != nil {
+		return err
+	}
+	if ev.Type == uinput.EvSyn && ev.Code == uinput.SynReport {
+		_, err = fmt.Fprintf(w, "\t# ------------ SYN_REPORT (0) ---------- +%dms", sincePreviousFrame/time.Millisecond)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintln(w)
+	return err
+}
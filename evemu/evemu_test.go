@@ -0,0 +1,82 @@
+package evemu
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const recording = `# EVEMU 1.3
+# Input device name: "Test Keyboard"
+N: Test Keyboard
+I: 0003 4711 0815 0001
+################################
+#      Waiting for events      #
+################################
+E: 0.000001 0004 0004 458756	# EV_MSC / MSC_SCAN             458756
+E: 0.000001 0001 001e 0001	# EV_KEY / KEY_A                1
+E: 0.000001 0000 0000 0000	# ------------ SYN_REPORT (0) ---------- +0ms
+E: 0.100250 0002 0000 -001	# EV_REL / REL_X                -1
+E: 0.100250 0000 0000 0000	# ------------ SYN_REPORT (0) ---------- +100ms
+`
+
+func TestReadEvents(t *testing.T) {
+	events, err := ReadEvents(strings.NewReader(recording))
+	if err != nil {
+		t.Fatalf("Failed to read events: %v", err)
+	}
+
+	expected := []Event{
+		{Time: time.Microsecond, Type: 0x04, Code: 0x04, Value: 458756},
+		{Time: time.Microsecond, Type: 0x01, Code: 0x1e, Value: 1},
+		{Time: time.Microsecond, Type: 0x00, Code: 0x00, Value: 0},
+		{Time: 100250 * time.Microsecond, Type: 0x02, Code: 0x00, Value: -1},
+		{Time: 100250 * time.Microsecond, Type: 0x00, Code: 0x00, Value: 0},
+	}
+	if !reflect.DeepEqual(expected, events) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, events)
+	}
+}
+
+func TestReadEventsRejectsMalformedEvents(t *testing.T) {
+	for _, line := range []string{
+		"E: 0.000001 0001 001e",
+		"E: 0.1 0001 001e 0001",
+		"E: 0.000001 0001 zzzz 0001",
+		"E: 0.000001 0001 001e one",
+	} {
+		_, err := ReadEvents(strings.NewReader(line))
+		if err == nil {
+			t.Fatalf("Expected %q to be rejected", line)
+		}
+	}
+}
+
+func TestWriteEventMatchesEvemuFormat(t *testing.T) {
+	var buf bytes.Buffer
+	for _, ev := range []struct {
+		event              Event
+		sincePreviousFrame time.Duration
+	}{
+		{Event{Time: 1500 * time.Millisecond, Type: 0x02, Code: 0x00, Value: -1}, 0},
+		{Event{Time: 1500 * time.Millisecond, Type: 0x00, Code: 0x00, Value: 0}, 12 * time.Millisecond},
+	} {
+		err := WriteEvent(&buf, ev.event, ev.sincePreviousFrame)
+		if err != nil {
+			t.Fatalf("Failed to write event: %v", err)
+		}
+	}
+
+	expected := "E: 1.500000 0002 0000 -001\n" +
+		"E: 1.500000 0000 0000 0000\t# ------------ SYN_REPORT (0) ---------- +12ms\n"
+	if buf.String() != expected {
+		t.Fatalf("Expected: %q\nActual: %q", expected, buf.String())
+	}
+
+	events, err := ReadEvents(&buf)
+	if err != nil || len(events) != 2 || events[0].Value != -1 || events[0].Time != 1500*time.Millisecond {
+		t.Fatalf("Expected written events to be read back, but got %+v (error: %v)", events, err)
+	}
+}
@@ -0,0 +1,97 @@
+package evemu
+
+import (
+	"context"
+	"time"
+
+	"github.com/bendahl/uinput"
+)
+
+// EventWriter is a device that recorded events are replayed with, such as a uinput.Device.
+type EventWriter interface {
+	// WriteEvents writes the given events followed by a sync event as a single frame.
+	WriteEvents(events ...uinput.Event) error
+}
+
+// PlayOptions control the playback of recorded events.
+type PlayOptions struct {
+	// Speed is the factor by which playback is sped up, e.g. 2 plays twice as fast as recorded (1 by default).
+	Speed float64
+
+	// Loops is the number of times the events are played (1 by default). A negative number of loops plays the
+	// events until the context is done.
+	Loops int
+}
+
+// frame holds the events between two sync events along with the time of the closing sync event.
+type frame struct {
+	time   time.Duration
+	events []uinput.Event
+}
+
+// Play will replay the events with their original timing, adjusted by the given speed. Each frame is written as soon
+// as its sync event (SYN_REPORT) is due. Play returns once all events have been played or the context is done.
+func Play(ctx context.Context, w EventWriter, events []Event, options PlayOptions) error {
+	speed := options.Speed
+	if speed <= 0 {
+		speed = 1
+	}
+	loops := options.Loops
+	if loops == 0 {
+		loops = 1
+	}
+
+	frames := toFrames(events)
+	for loop := 0; loops < 0 || loop < loops; loop++ {
+		start := time.Now()
+		for _, f := range frames {
+			due := start.Add(time.Duration(float64(f.time) / speed))
+			err := waitUntil(ctx, due)
+			if err != nil {
+				return err
+			}
+			err = w.WriteEvents(f.events...)
+			if err != nil {
+				return err
+			}
+		}
+		if len(frames) == 0 {
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// toFrames groups the events into frames. Sync events are dropped, since the device adds them when writing a frame.
+func toFrames(events []Event) []frame {
+	var frames []frame
+	var current frame
+	for _, ev := range events {
+		if ev.Type != uinput.EvSyn {
+			current.events = append(current.events, uinput.Event{Type: ev.Type, Code: ev.Code, Value: ev.Value})
+			continue
+		}
+		if ev.Code != uinput.SynReport || len(current.events) == 0 {
+			continue
+		}
+		current.time = ev.Time
+		frames = append(frames, current)
+		current = frame{}
+	}
+	if len(current.events) > 0 {
+		current.time = events[len(events)-1].Time
+		frames = append(frames, current)
+	}
+	return frames
+}
+
+func waitUntil(ctx context.Context, due time.Time) error {
+	timer := time.NewTimer(time.Until(due))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,95 @@
+package evemu
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bendahl/uinput"
+)
+
+func createReplayDevice(t *testing.T, fake *uinput.FakeBackend) uinput.Device {
+	dev, err := uinput.NewDeviceBuilder("/dev/uinput").
+		Name([]byte("Replay Device")).
+		Keys(uinput.KeyA).
+		RelAxes(uinput.RelX).
+		MiscEvents(uinput.MscScan).
+		Create(uinput.WithFakeBackend(fake))
+	if err != nil {
+		t.Fatalf("Failed to create the replay device. Last error was: %s\n", err)
+	}
+	return dev
+}
+
+func TestPlayWritesFramesWithOriginalTiming(t *testing.T) {
+	events, err := ReadEvents(strings.NewReader(recording))
+	if err != nil {
+		t.Fatalf("Failed to read events: %v", err)
+	}
+	fake := uinput.NewFakeBackend()
+	dev := createReplayDevice(t, fake)
+	defer dev.Close()
+
+	start := time.Now()
+	err = Play(context.Background(), dev, events, PlayOptions{})
+	if err != nil {
+		t.Fatalf("Failed to play events: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Fatalf("Expected playback to take at least 100ms, but it took %v", elapsed)
+	}
+
+	expected := []uinput.Event{
+		{Type: 0x04, Code: 0x04, Value: 458756}, {Type: 0x01, Code: 0x1e, Value: 1}, {Type: 0x00, Code: 0x00},
+		{Type: 0x02, Code: 0x00, Value: -1}, {Type: 0x00, Code: 0x00},
+	}
+	if actual := fake.Events(); !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, actual)
+	}
+}
+
+func TestPlayWithSpeedAndLoops(t *testing.T) {
+	events := []Event{
+		{Time: 0, Type: 0x02, Code: 0x00, Value: 1},
+		{Time: 0, Type: 0x00, Code: 0x00},
+		{Time: time.Second, Type: 0x02, Code: 0x00, Value: 2},
+		{Time: time.Second, Type: 0x00, Code: 0x00},
+	}
+	fake := uinput.NewFakeBackend()
+	dev := createReplayDevice(t, fake)
+	defer dev.Close()
+
+	start := time.Now()
+	err := Play(context.Background(), dev, events, PlayOptions{Speed: 20, Loops: 3})
+	if err != nil {
+		t.Fatalf("Failed to play events: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond || elapsed > time.Second {
+		t.Fatalf("Expected three loops at 20x speed to take about 150ms, but they took %v", elapsed)
+	}
+	if n := len(fake.Events()); n != 12 {
+		t.Fatalf("Expected 12 events to be written, but got %d", n)
+	}
+}
+
+func TestPlayEndsOnceContextIsDone(t *testing.T) {
+	events := []Event{
+		{Time: 0, Type: 0x02, Code: 0x00, Value: 1},
+		{Time: 0, Type: 0x00, Code: 0x00},
+		{Time: time.Hour, Type: 0x02, Code: 0x00, Value: 2},
+		{Time: time.Hour, Type: 0x00, Code: 0x00},
+	}
+	fake := uinput.NewFakeBackend()
+	dev := createReplayDevice(t, fake)
+	defer dev.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := Play(ctx, dev, events, PlayOptions{Loops: -1})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected playback to end with the context's error, but got: %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package evemu
+
+import (
+	"errors"
+	"io"
+	"os"
+	"time"
+
+	"github.com/bendahl/uinput"
+	"github.com/bendahl/uinput/evdev"
+)
+
+// EventReader is a source of events, such as an opened evdev.Device.
+type EventReader interface {
+	ReadEvent() (evdev.Event, error)
+}
+
+// Recorder writes events in evemu format. The time of each event is stored relative to the first recorded event.
+type Recorder struct {
+	w         io.Writer
+	start     time.Time
+	lastFrame time.Time
+}
+
+// NewRecorder will create a new recorder that writes to w.
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{w: w}
+}
+
+// WriteEvent will write a single event that has been read from an event node.
+func (r *Recorder) WriteEvent(ev evdev.Event) error {
+	if r.start.IsZero() {
+		r.start = ev.Time
+		r.lastFrame = ev.Time
+	}
+	var sincePreviousFrame time.Duration
+	if ev.Type == uinput.EvSyn && ev.Code == uinput.SynReport {
+		sincePreviousFrame = ev.Time.Sub(r.lastFrame)
+		r.lastFrame = ev.Time
+	}
+	return WriteEvent(r.w, Event{Time: ev.Time.Sub(r.start), Type: ev.Type, Code: ev.Code, Value: ev.Value}, sincePreviousFrame)
+}
+
+// Record will read events from the source and write them to w until reading fails. Closing the source (an
+// evdev.Device, for example) ends the recording without an error.
+func Record(w io.Writer, source EventReader) error {
+	r := NewRecorder(w)
+	for {
+		ev, err := source.ReadEvent()
+		if errors.Is(err, os.ErrClosed) || errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		err = r.WriteEvent(ev)
+		if err != nil {
+			return err
+		}
+	}
+}
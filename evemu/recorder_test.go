@@ -0,0 +1,47 @@
+package evemu
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/bendahl/uinput/evdev"
+)
+
+type eventSource struct {
+	events []evdev.Event
+}
+
+func (s *eventSource) ReadEvent() (evdev.Event, error) {
+	if len(s.events) == 0 {
+		return evdev.Event{}, os.ErrClosed
+	}
+	ev := s.events[0]
+	s.events = s.events[1:]
+	return ev, nil
+}
+
+func TestRecordUsesRelativeTimestamps(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	source := &eventSource{events: []evdev.Event{
+		{Time: start, Type: 0x01, Code: 0x1e, Value: 1},
+		{Time: start, Type: 0x00, Code: 0x00, Value: 0},
+		{Time: start.Add(50 * time.Millisecond), Type: 0x01, Code: 0x1e, Value: 0},
+		{Time: start.Add(50 * time.Millisecond), Type: 0x00, Code: 0x00, Value: 0},
+	}}
+
+	var buf bytes.Buffer
+	err := Record(&buf, source)
+	if err != nil {
+		t.Fatalf("Expected recording to end without an error once the source is closed, but got: %v", err)
+	}
+
+	expected := "E: 0.000000 0001 001e 0001\n" +
+		"E: 0.000000 0000 0000 0000\t# ------------ SYN_REPORT (0) ---------- +0ms\n" +
+		"E: 0.050000 0001 001e 0000\n" +
+		"E: 0.050000 0000 0000 0000\t# ------------ SYN_REPORT (0) ---------- +50ms\n"
+	if buf.String() != expected {
+		t.Fatalf("Expected: %q\nActual: %q", expected, buf.String())
+	}
+}
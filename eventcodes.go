@@ -0,0 +1,138 @@
+package uinput
+
+// Event types (EV_*) as specified in input-event-codes.h
+const (
+	EvSyn = 0x00
+	EvKey = 0x01
+	EvRel = 0x02
+	EvAbs = 0x03
+	EvMsc = 0x04
+	EvSw  = 0x05
+	EvLed = 0x11
+	EvSnd = 0x12
+	EvRep = 0x14
+	EvFF  = 0x15
+)
+
+// Sync events (SYN_*). A SYN_REPORT event ends each frame of events.
+const (
+	SynReport   = 0x00
+	SynConfig   = 0x01
+	SynMtReport = 0x02
+	SynDropped  = 0x03
+)
+
+// Relative axes (REL_*). The high resolution wheel axes report fractions of a notch, where 120 is a full notch.
+const (
+	RelX           = 0x00
+	RelY           = 0x01
+	RelZ           = 0x02
+	RelRX          = 0x03
+	RelRY          = 0x04
+	RelRZ          = 0x05
+	RelHWheel      = 0x06
+	RelDial        = 0x07
+	RelWheel       = 0x08
+	RelMisc        = 0x09
+	RelWheelHiRes  = 0x0b
+	RelHWheelHiRes = 0x0c
+)
+
+// Absolute axes (ABS_*)
+const (
+	AbsX         = 0x00
+	AbsY         = 0x01
+	AbsZ         = 0x02
+	AbsRX        = 0x03
+	AbsRY        = 0x04
+	AbsRZ        = 0x05
+	AbsThrottle  = 0x06
+	AbsRudder    = 0x07
+	AbsWheel     = 0x08
+	AbsGas       = 0x09
+	AbsBrake     = 0x0a
+	AbsHat0X     = 0x10
+	AbsHat0Y     = 0x11
+	AbsHat1X     = 0x12
+	AbsHat1Y     = 0x13
+	AbsHat2X     = 0x14
+	AbsHat2Y     = 0x15
+	AbsHat3X     = 0x16
+	AbsHat3Y     = 0x17
+	AbsPressure  = 0x18
+	AbsDistance  = 0x19
+	AbsTiltX     = 0x1a
+	AbsTiltY     = 0x1b
+	AbsToolWidth = 0x1c
+	AbsVolume    = 0x20
+	AbsMisc      = 0x28
+
+	AbsMtSlot        = 0x2f
+	AbsMtTouchMajor  = 0x30
+	AbsMtTouchMinor  = 0x31
+	AbsMtWidthMajor  = 0x32
+	AbsMtWidthMinor  = 0x33
+	AbsMtOrientation = 0x34
+	AbsMtPositionX   = 0x35
+	AbsMtPositionY   = 0x36
+	AbsMtToolType    = 0x37
+	AbsMtBlobID      = 0x38
+	AbsMtTrackingID  = 0x39
+	AbsMtPressure    = 0x3a
+	AbsMtDistance    = 0x3b
+	AbsMtToolX       = 0x3c
+	AbsMtToolY       = 0x3d
+)
+
+// Miscellaneous events (MSC_*). MSC_SCAN reports the scan code of a key and usually precedes the key event.
+const (
+	MscSerial    = 0x00
+	MscPulseLed  = 0x01
+	MscGesture   = 0x02
+	MscRaw       = 0x03
+	MscScan      = 0x04
+	MscTimestamp = 0x05
+)
+
+// Switches (SW_*)
+const (
+	SwLid                = 0x00
+	SwTabletMode         = 0x01
+	SwHeadphoneInsert    = 0x02
+	SwRfkillAll          = 0x03
+	SwMicrophoneInsert   = 0x04
+	SwDock               = 0x05
+	SwLineoutInsert      = 0x06
+	SwJackPhysicalInsert = 0x07
+	SwVideooutInsert     = 0x08
+	SwCameraLensCover    = 0x09
+	SwKeypadSlide        = 0x0a
+	SwFrontProximity     = 0x0b
+	SwRotateLock         = 0x0c
+	SwLineinInsert       = 0x0d
+	SwMuteDevice         = 0x0e
+	SwPenInserted        = 0x0f
+	SwMachineCover       = 0x10
+)
+
+// LEDs (LED_*)
+const (
+	LedNumLock    = 0x00
+	LedCapsLock   = 0x01
+	LedScrollLock = 0x02
+	LedCompose    = 0x03
+	LedKana       = 0x04
+	LedSleep      = 0x05
+	LedSuspend    = 0x06
+	LedMute       = 0x07
+	LedMisc       = 0x08
+	LedMail       = 0x09
+	LedCharging   = 0x0a
+)
+
+// Sounds (SND_*)
+const (
+	SndClick = 0x00
+	SndBell  = 0x01
+	SndTone  = 0x02
+)
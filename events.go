@@ -0,0 +1,35 @@
+package uinput
+
+import "time"
+
+// Event is a single input event. See eventcodes.go and keycodes.go for the available types and codes.
+type Event struct {
+	// Time is the time the event occurred. It is optional and ignored when events are written to a device, since the
+	// kernel sets the time of injected events itself.
+	Time time.Time
+
+	Type  uint16
+	Code  uint16
+	Value int32
+}
+
+// capabilities holds the codes that have been registered for a device, per event type.
+type capabilities map[uint16]map[int]bool
+
+// add registers the given codes of an event type.
+func (c capabilities) add(evType uint16, codes ...int) {
+	if c[evType] == nil {
+		c[evType] = make(map[int]bool)
+	}
+	for _, code := range codes {
+		c[evType][code] = true
+	}
+}
+
+// assertRegistered returns an error if the given code has not been registered.
+func (c capabilities) assertRegistered(evType uint16, code int) error {
+	if !c[evType][code] {
+		return capabilityGroup{evType: evType}.rangeError("code %d of event type %d has not been registered for this device", code, evType)
+	}
+	return nil
+}
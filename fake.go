@@ -0,0 +1,199 @@
+package uinput
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// Ioctl is a request that has been issued on a FakeBackend.
+type Ioctl struct {
+	// Name is the name of the request as defined in linux/uinput.h, for example UI_SET_EVBIT.
+	Name    string
+	Request uintptr
+
+	// Arg is the integer argument of the request, for example the event type for UI_SET_EVBIT. It is 0 for all
+	// requests that take a pointer.
+	Arg uintptr
+
+	// Data holds a copy of the struct or string passed to UI_DEV_SETUP, UI_ABS_SETUP and UI_SET_PHYS.
+	Data []byte
+}
+
+// FakeBackend records all requests issued while creating a device, as well as all events written to it. It allows
+// to test code that uses this package without access to /dev/uinput (see WithFakeBackend).
+//
+// Example:
+//
+//	fake := uinput.NewFakeBackend()
+//	keyboard, err := uinput.CreateKeyboard("/dev/uinput", []byte("test"), uinput.WithFakeBackend(fake))
+//	...
+//	events := fake.Events()
+type FakeBackend struct {
+	mutex   sync.Mutex
+	ioctls  []Ioctl
+	events  []Event
+	pending []byte
+	closed  bool
+	done    chan struct{}
+
+	// failures maps requests that are to fail to the error code they fail with, see failRequest.
+	failures map[[2]uintptr]syscall.Errno
+	// closeCalls counts the calls to Close, including those on a closed backend.
+	closeCalls int
+}
+
+// NewFakeBackend will create a new fake backend.
+func NewFakeBackend() *FakeBackend {
+	return &FakeBackend{done: make(chan struct{})}
+}
+
+var ioctlNames = map[uintptr]string{
+	uiDevCreate:     "UI_DEV_CREATE",
+	uiDevDestroy:    "UI_DEV_DESTROY",
+	uiDevSetup:      "UI_DEV_SETUP",
+	uiAbsSetup:      "UI_ABS_SETUP",
+	uiGetVersion:    "UI_GET_VERSION",
+	uiGetSysname:    "UI_GET_SYSNAME",
+	uiSetEvBit:      "UI_SET_EVBIT",
+	uiSetKeyBit:     "UI_SET_KEYBIT",
+	uiSetRelBit:     "UI_SET_RELBIT",
+	uiSetAbsBit:     "UI_SET_ABSBIT",
+	uiSetMscBit:     "UI_SET_MSCBIT",
+	uiSetLedBit:     "UI_SET_LEDBIT",
+	uiSetSndBit:     "UI_SET_SNDBIT",
+	uiSetFFBit:      "UI_SET_FFBIT",
+	uiSetSwBit:      "UI_SET_SWBIT",
+	uiSetProp:       "UI_SET_PROPBIT",
+	uiSetPhys:       "UI_SET_PHYS",
+	uiBeginFFUpload: "UI_BEGIN_FF_UPLOAD",
+	uiEndFFUpload:   "UI_END_FF_UPLOAD",
+	uiBeginFFErase:  "UI_BEGIN_FF_ERASE",
+	uiEndFFErase:    "UI_END_FF_ERASE",
+}
+
+// Ioctls will return all requests issued so far.
+func (f *FakeBackend) Ioctls() []Ioctl {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]Ioctl(nil), f.ioctls...)
+}
+
+// Events will return all events written so far, including the sync events that end each frame.
+func (f *FakeBackend) Events() []Event {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]Event(nil), f.events...)
+}
+
+// Closed will return whether the device has been closed.
+func (f *FakeBackend) Closed() bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.closed
+}
+
+// Read blocks until the backend is closed, since no events are ever sent back to a fake device.
+func (f *FakeBackend) Read(p []byte) (int, error) {
+	<-f.done
+	return 0, io.EOF
+}
+
+// Write decodes and records the given events.
+func (f *FakeBackend) Write(p []byte) (int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.closed {
+		return 0, os.ErrClosed
+	}
+	f.pending = append(f.pending, p...)
+	for len(f.pending) >= inputEventSize {
+		iev := inputEventFromBuffer(f.pending)
+		f.events = append(f.events, Event{Type: iev.Type, Code: iev.Code, Value: iev.Value})
+		f.pending = f.pending[inputEventSize:]
+	}
+	return len(p), nil
+}
+
+// Close marks the backend as closed.
+func (f *FakeBackend) Close() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.closeCalls++
+	if f.closed {
+		return os.ErrClosed
+	}
+	f.closed = true
+	close(f.done)
+	return nil
+}
+
+// failRequest makes all further requests with the given integer argument fail with errno, in order to test error
+// handling.
+func (f *FakeBackend) failRequest(cmd uintptr, arg uintptr, errno syscall.Errno) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.failures == nil {
+		f.failures = make(map[[2]uintptr]syscall.Errno)
+	}
+	f.failures[[2]uintptr{cmd, arg}] = errno
+}
+
+func (f *FakeBackend) ioctl(cmd uintptr, arg uintptr) error {
+	err := f.record(Ioctl{Name: ioctlNames[cmd], Request: cmd, Arg: arg})
+	if err != nil {
+		return err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if errno, ok := f.failures[[2]uintptr{cmd, arg}]; ok {
+		return &IoctlError{Request: cmd, Errno: errno}
+	}
+	return nil
+}
+
+func (f *FakeBackend) ioctlPtr(cmd uintptr, ptr unsafe.Pointer) error {
+	request := Ioctl{Name: ioctlNames[cmd], Request: cmd}
+	switch cmd {
+	case uiGetVersion:
+		*(*uint32)(ptr) = uinputSetupVersion
+	case uiDevSetup:
+		request.Data = append([]byte(nil), (*[unsafe.Sizeof(uinputSetup{})]byte)(ptr)[:]...)
+	case uiAbsSetup:
+		request.Data = append([]byte(nil), (*[unsafe.Sizeof(uinputAbsSetup{})]byte)(ptr)[:]...)
+	case uiSetPhys:
+		// the path is NUL terminated; read it byte by byte, so that nothing past the end of the buffer is accessed
+		for i := uintptr(0); ; i++ {
+			c := *(*byte)(unsafe.Pointer(uintptr(ptr) + i))
+			if c == 0 {
+				break
+			}
+			request.Data = append(request.Data, c)
+		}
+	default:
+		// requests that rely on the kernel (fetching the sysfs name, force feedback) are not supported
+		err := f.record(request)
+		if err != nil {
+			return err
+		}
+		return &IoctlError{Request: cmd, Errno: syscall.ENOTTY}
+	}
+	return f.record(request)
+}
+
+func (f *FakeBackend) record(request Ioctl) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.closed {
+		return closedError(os.ErrClosed)
+	}
+	f.ioctls = append(f.ioctls, request)
+	return nil
+}
+
+func (f *FakeBackend) waitUntilReady(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,152 @@
+package uinput
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"syscall"
+	"testing"
+)
+
+func TestFakeBackendRecordsDeviceCreation(t *testing.T) {
+	fake := NewFakeBackend()
+	vm, err := CreateMouse("/does/not/exist", []byte("Fake Mouse"), WithFakeBackend(fake), WithProduct(0x0819))
+	if err != nil {
+		t.Fatalf("Failed to create the fake mouse. Last error was: %s\n", err)
+	}
+	err = vm.Close()
+	if err != nil {
+		t.Fatalf("Failed to close device. Last error was: %s\n", err)
+	}
+
+	var names []string
+	var setup []byte
+	for _, request := range fake.Ioctls() {
+		names = append(names, request.Name)
+		if request.Request == uiDevSetup {
+			setup = request.Data
+		}
+	}
+	expected := []string{
+		"UI_SET_EVBIT", "UI_SET_KEYBIT", "UI_SET_KEYBIT", "UI_SET_KEYBIT",
+		"UI_SET_EVBIT", "UI_SET_RELBIT", "UI_SET_RELBIT", "UI_SET_RELBIT", "UI_SET_RELBIT",
+		"UI_GET_VERSION", "UI_DEV_SETUP", "UI_DEV_CREATE", "UI_DEV_DESTROY",
+	}
+	if !reflect.DeepEqual(expected, names) {
+		t.Fatalf("Expected: %v\nActual: %v", expected, names)
+	}
+	if !bytes.HasPrefix(setup, []byte{0x03, 0x00, 0x11, 0x47, 0x19, 0x08, 0x01, 0x00, 'F', 'a', 'k', 'e'}) {
+		t.Fatalf("Unexpected device setup: %x", setup)
+	}
+	if !fake.Closed() {
+		t.Fatalf("Expected fake backend to be closed")
+	}
+}
+
+func TestFakeBackendRecordsEvents(t *testing.T) {
+	fake := NewFakeBackend()
+	vk, err := CreateKeyboard("", []byte("Fake Keyboard"), WithFakeBackend(fake))
+	if err != nil {
+		t.Fatalf("Failed to create the fake keyboard. Last error was: %s\n", err)
+	}
+
+	err = vk.KeyPress(KeyA)
+	if err != nil {
+		t.Fatalf("Failed to send key press. Last error was: %s\n", err)
+	}
+	err = vk.KeyDown(KeyLeftshift)
+	if err != nil {
+		t.Fatalf("Failed to send key down event. Last error was: %s\n", err)
+	}
+	// the held key is released when closing the device
+	err = vk.Close()
+	if err != nil {
+		t.Fatalf("Failed to close device. Last error was: %s\n", err)
+	}
+
+	expected := []Event{
+		{Type: EvKey, Code: KeyA, Value: 1}, {Type: EvSyn, Code: SynReport},
+		{Type: EvKey, Code: KeyA, Value: 0}, {Type: EvSyn, Code: SynReport},
+		{Type: EvKey, Code: KeyLeftshift, Value: 1}, {Type: EvSyn, Code: SynReport},
+		{Type: EvKey, Code: KeyLeftshift, Value: 0}, {Type: EvSyn, Code: SynReport},
+	}
+	if actual := fake.Events(); !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, actual)
+	}
+
+	err = vk.KeyPress(KeyA)
+	if !errors.Is(err, ErrDeviceClosed) {
+		t.Fatalf("Expected key press on closed device to fail with ErrDeviceClosed, but got: %v", err)
+	}
+}
+
+func TestFakeBackendRecordsPhysAndProperties(t *testing.T) {
+	fake := NewFakeBackend()
+	_, err := CreateTouchPad("", []byte("Fake TouchPad"), 0, 1024, 0, 768,
+		WithFakeBackend(fake), WithPhys("fake/input0"), WithProperties(PropPointer))
+	if err != nil {
+		t.Fatalf("Failed to create the fake touch pad. Last error was: %s\n", err)
+	}
+
+	var phys []byte
+	var properties []uintptr
+	for _, request := range fake.Ioctls() {
+		switch request.Request {
+		case uiSetPhys:
+			phys = request.Data
+		case uiSetProp:
+			properties = append(properties, request.Arg)
+		}
+	}
+	if string(phys) != "fake/input0" || !reflect.DeepEqual(properties, []uintptr{uintptr(PropPointer)}) {
+		t.Fatalf("Unexpected phys %q or properties %v", phys, properties)
+	}
+}
+
+func TestDeviceCreationClosesBackendOnceOnFailure(t *testing.T) {
+	create := map[string]func(opts ...Option) error{
+		"keyboard": func(opts ...Option) error {
+			_, err := CreateKeyboard("/dev/uinput", []byte("Test Keyboard"), opts...)
+			return err
+		},
+		"mouse": func(opts ...Option) error {
+			_, err := CreateMouse("/dev/uinput", []byte("Test Mouse"), opts...)
+			return err
+		},
+		"dial": func(opts ...Option) error {
+			_, err := CreateDial("/dev/uinput", []byte("Test Dial"), opts...)
+			return err
+		},
+		"touch pad": func(opts ...Option) error {
+			_, err := CreateTouchPad("/dev/uinput", []byte("Test TouchPad"), 0, 1024, 0, 768, opts...)
+			return err
+		},
+		"multi touch": func(opts ...Option) error {
+			_, err := CreateMultiTouch("/dev/uinput", []byte("Test MultiTouch"), 0, 1024, 0, 768, 2, opts...)
+			return err
+		},
+	}
+	tests := []struct {
+		device string
+		evType uintptr
+	}{
+		{"keyboard", EvKey}, {"keyboard", EvLed},
+		{"mouse", EvKey}, {"mouse", EvRel},
+		{"dial", EvRel},
+		{"touch pad", EvKey}, {"touch pad", EvAbs},
+		{"multi touch", EvKey}, {"multi touch", EvAbs},
+	}
+
+	for _, test := range tests {
+		fake := NewFakeBackend()
+		fake.failRequest(uiSetEvBit, test.evType, syscall.EINVAL)
+		err := create[test.device](WithFakeBackend(fake))
+		if err == nil {
+			t.Fatalf("Expected %s creation to fail if event type %d can not be registered", test.device, test.evType)
+		}
+		if fake.closeCalls != 1 {
+			t.Fatalf("Expected the %s backend to be closed once if event type %d can not be registered, but it was closed %d times",
+				test.device, test.evType, fake.closeCalls)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package uinput
+
+import (
+	"encoding/binary"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// FFEventKind specifies the kind of force feedback request issued by the application using the device.
+type FFEventKind int
+
+const (
+	// FFUpload is issued when an application uploads a new effect or updates an existing one.
+	FFUpload FFEventKind = iota + 1
+	// FFErase is issued when an application removes an effect.
+	FFErase
+	// FFPlay is issued when an application starts playing an effect.
+	FFPlay
+	// FFStop is issued when an application stops playing an effect.
+	FFStop
+	// FFSetGain is issued when an application changes the overall strength of all effects.
+	FFSetGain
+)
+
+// FFEffectType is the type of force feedback effect.
+type FFEffectType uint16
+
+const (
+	FFRumble   FFEffectType = ffRumble
+	FFPeriodic FFEffectType = ffPeriodic
+	FFConstant FFEffectType = ffConstant
+)
+
+// FFEvent is a force feedback request received from an application that uses the device.
+type FFEvent struct {
+	Kind FFEventKind
+
+	// EffectID identifies the effect the request refers to (not set for FFSetGain).
+	EffectID int16
+
+	// Effect holds the uploaded effect (only set for FFUpload).
+	Effect FFEffect
+
+	// Value holds the number of repetitions for FFPlay and the gain (0-0xffff) for FFSetGain.
+	Value int32
+}
+
+// FFEffect describes a force feedback effect. Depending on the Type, either Rumble, Periodic or Constant is set.
+type FFEffect struct {
+	Type      FFEffectType
+	ID        int16
+	Direction uint16
+	Length    time.Duration
+	Delay     time.Duration
+
+	Rumble   FFRumbleEffect
+	Periodic FFPeriodicEffect
+	Constant FFConstantEffect
+}
+
+// FFRumbleEffect describes a rumble effect. Most controllers use two motors of different strength.
+type FFRumbleEffect struct {
+	StrongMagnitude uint16
+	WeakMagnitude   uint16
+}
+
+// FFPeriodicEffect describes a periodic effect, for example a sine wave.
+type FFPeriodicEffect struct {
+	Waveform  uint16
+	Period    time.Duration
+	Magnitude int16
+	Offset    int16
+	Phase     uint16
+	Envelope  FFEnvelope
+}
+
+// FFConstantEffect describes an effect with constant strength.
+type FFConstantEffect struct {
+	Level    int16
+	Envelope FFEnvelope
+}
+
+// FFEnvelope describes how an effect fades in and out.
+type FFEnvelope struct {
+	AttackLength time.Duration
+	AttackLevel  uint16
+	FadeLength   time.Duration
+	FadeLevel    uint16
+}
+
+// A ForceFeedbackGamepad is a Gamepad that accepts force feedback (rumble) effects from applications.
+type ForceFeedbackGamepad interface {
+	Gamepad
+
+	// Effects returns the channel on which all force feedback requests are delivered. The channel must be drained
+	// continuously, since further requests are delayed until the previous one has been received.
+	// It will be closed once the device is closed.
+	Effects() <-chan FFEvent
+}
+
+type vFFGamepad struct {
+	vGamepad
+	effects   chan FFEvent
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// CreateForceFeedbackGamepad will create a new gamepad that supports rumble, periodic and constant force feedback
+// effects. The effectsMax parameter specifies how many effects may be uploaded to the device at the same time.
+func CreateForceFeedbackGamepad(path string, name []byte, vendor uint16, product uint16, effectsMax uint32, opts ...Option) (ForceFeedbackGamepad, error) {
+	err := validateDevicePath(path, opts)
+	if err != nil {
+		return nil, err
+	}
+	err = validateUinputName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	fd, err := createVGamepadDevice(path, name, vendor, product, effectsMax, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	vg := &vFFGamepad{
+		vGamepad: vGamepad{name: name, dev: newUinputDevice(fd, gamepadCapabilities(), gamepadRestAxes...)},
+		effects:  make(chan FFEvent, 16),
+		done:     make(chan struct{}),
+	}
+	go func() {
+		readEvents(fd, vg.handleEvent)
+		close(vg.effects)
+	}()
+
+	return vg, nil
+}
+
+func (vg *vFFGamepad) Effects() <-chan FFEvent {
+	return vg.effects
+}
+
+// Close closes the device and releases the device. The effects channel will be closed as well.
+func (vg *vFFGamepad) Close() error {
+	vg.closeOnce.Do(func() { close(vg.done) })
+	return vg.vGamepad.Close()
+}
+
+func (vg *vFFGamepad) handleEvent(iev inputEvent) {
+	switch iev.Type {
+	case evUinput:
+		switch iev.Code {
+		case uiFFUpload:
+			vg.handleUpload(uint32(iev.Value))
+		case uiFFErase:
+			vg.handleErase(uint32(iev.Value))
+		}
+	case EvFF:
+		if iev.Code == ffGain {
+			vg.deliver(FFEvent{Kind: FFSetGain, Value: iev.Value})
+			return
+		}
+		kind := FFPlay
+		if iev.Value == 0 {
+			kind = FFStop
+		}
+		vg.deliver(FFEvent{Kind: kind, EffectID: int16(iev.Code), Value: iev.Value})
+	}
+}
+
+// handleUpload completes the upload request, which is required for the kernel to accept the effect.
+func (vg *vFFGamepad) handleUpload(requestID uint32) {
+	upload := uinputFFUpload{RequestID: requestID}
+	err := ioctlPtr(vg.dev.file, uiBeginFFUpload, unsafe.Pointer(&upload))
+	if err != nil {
+		return
+	}
+	upload.Retval = 0
+	err = ioctlPtr(vg.dev.file, uiEndFFUpload, unsafe.Pointer(&upload))
+	if err != nil {
+		return
+	}
+	vg.deliver(FFEvent{Kind: FFUpload, EffectID: upload.Effect.ID, Effect: upload.Effect.toFFEffect()})
+}
+
+// handleErase completes the erase request, which is required for the kernel to remove the effect.
+func (vg *vFFGamepad) handleErase(requestID uint32) {
+	erase := uinputFFErase{RequestID: requestID}
+	err := ioctlPtr(vg.dev.file, uiBeginFFErase, unsafe.Pointer(&erase))
+	if err != nil {
+		return
+	}
+	erase.Retval = 0
+	err = ioctlPtr(vg.dev.file, uiEndFFErase, unsafe.Pointer(&erase))
+	if err != nil {
+		return
+	}
+	vg.deliver(FFEvent{Kind: FFErase, EffectID: int16(erase.EffectID)})
+}
+
+func (vg *vFFGamepad) deliver(ev FFEvent) {
+	select {
+	case vg.effects <- ev:
+	case <-vg.done:
+	}
+}
+
+// toFFEffect decodes the kernel's effect struct, including the type specific part of the effect union.
+func (e ffEffect) toFFEffect() FFEffect {
+	u := (*[ffEffectUnionSize]byte)(unsafe.Pointer(&e.U))[:]
+	effect := FFEffect{
+		Type:      FFEffectType(e.Type),
+		ID:        e.ID,
+		Direction: e.Direction,
+		Length:    time.Duration(e.Replay.Length) * time.Millisecond,
+		Delay:     time.Duration(e.Replay.Delay) * time.Millisecond,
+	}
+
+	switch effect.Type {
+	case FFRumble:
+		effect.Rumble = FFRumbleEffect{
+			StrongMagnitude: binary.LittleEndian.Uint16(u[0:]),
+			WeakMagnitude:   binary.LittleEndian.Uint16(u[2:])}
+	case FFPeriodic:
+		effect.Periodic = FFPeriodicEffect{
+			Waveform:  binary.LittleEndian.Uint16(u[0:]),
+			Period:    time.Duration(binary.LittleEndian.Uint16(u[2:])) * time.Millisecond,
+			Magnitude: int16(binary.LittleEndian.Uint16(u[4:])),
+			Offset:    int16(binary.LittleEndian.Uint16(u[6:])),
+			Phase:     binary.LittleEndian.Uint16(u[8:]),
+			Envelope:  decodeFFEnvelope(u[10:])}
+	case FFConstant:
+		effect.Constant = FFConstantEffect{
+			Level:    int16(binary.LittleEndian.Uint16(u[0:])),
+			Envelope: decodeFFEnvelope(u[2:])}
+	}
+	return effect
+}
+
+func decodeFFEnvelope(b []byte) FFEnvelope {
+	return FFEnvelope{
+		AttackLength: time.Duration(binary.LittleEndian.Uint16(b[0:])) * time.Millisecond,
+		AttackLevel:  binary.LittleEndian.Uint16(b[2:]),
+		FadeLength:   time.Duration(binary.LittleEndian.Uint16(b[4:])) * time.Millisecond,
+		FadeLevel:    binary.LittleEndian.Uint16(b[6:])}
+}
@@ -0,0 +1,140 @@
+package uinput
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+func TestForceFeedbackGamepadClosesEffectsChannel(t *testing.T) {
+	vg, err := CreateForceFeedbackGamepad("/dev/uinput", []byte("Rumbling gophers"), 0xDEAD, 0xBEEF, 16)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+
+	err = vg.ButtonPress(ButtonSouth)
+	if err != nil {
+		t.Fatalf("Failed to send button press. Last error was: %s\n", err)
+	}
+
+	err = vg.Close()
+	if err != nil {
+		t.Fatalf("Failed to close device. Last error was: %s\n", err)
+	}
+
+	select {
+	case _, ok := <-vg.Effects():
+		if ok {
+			t.Fatalf("Expected no force feedback events to be delivered")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Expected effects channel to be closed after closing the device")
+	}
+}
+
+func TestForceFeedbackStructSizesMatchKernelLayout(t *testing.T) {
+	effectSize, uploadSize := uintptr(48), uintptr(104)
+	if unsafe.Sizeof(uintptr(0)) == 4 {
+		effectSize, uploadSize = 44, 96
+	}
+
+	if size := unsafe.Sizeof(ffEffect{}); size != effectSize {
+		t.Fatalf("Expected ff_effect to be %d bytes long, but got %d", effectSize, size)
+	}
+	if size := unsafe.Sizeof(uinputFFUpload{}); size != uploadSize {
+		t.Fatalf("Expected uinput_ff_upload to be %d bytes long, but got %d", uploadSize, size)
+	}
+	if size := unsafe.Sizeof(uinputFFErase{}); size != 12 {
+		t.Fatalf("Expected uinput_ff_erase to be 12 bytes long, but got %d", size)
+	}
+	if offset := unsafe.Offsetof(ffEffect{}.U); offset != 16 {
+		t.Fatalf("Expected the effect union to start at offset 16, but got %d", offset)
+	}
+}
+
+func TestRumbleEffectDecoding(t *testing.T) {
+	e := ffEffect{Type: ffRumble, ID: 2, Replay: ffReplay{Length: 500, Delay: 10}}
+	u := (*[ffEffectUnionSize]byte)(unsafe.Pointer(&e.U))[:]
+	binary.LittleEndian.PutUint16(u[0:], 0xc000)
+	binary.LittleEndian.PutUint16(u[2:], 0x4000)
+
+	effect := e.toFFEffect()
+	expected := FFEffect{
+		Type:   FFRumble,
+		ID:     2,
+		Length: 500 * time.Millisecond,
+		Delay:  10 * time.Millisecond,
+		Rumble: FFRumbleEffect{StrongMagnitude: 0xc000, WeakMagnitude: 0x4000}}
+	if effect != expected {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, effect)
+	}
+}
+
+func TestPeriodicEffectDecoding(t *testing.T) {
+	e := ffEffect{Type: ffPeriodic, ID: 1, Direction: 0x4000}
+	u := (*[ffEffectUnionSize]byte)(unsafe.Pointer(&e.U))[:]
+	binary.LittleEndian.PutUint16(u[0:], ffSine)
+	binary.LittleEndian.PutUint16(u[2:], 100)
+	binary.LittleEndian.PutUint16(u[4:], 0x7fff)
+	binary.LittleEndian.PutUint16(u[6:], 0xfffe)
+	binary.LittleEndian.PutUint16(u[8:], 90)
+	binary.LittleEndian.PutUint16(u[10:], 20)
+	binary.LittleEndian.PutUint16(u[12:], 0x1000)
+	binary.LittleEndian.PutUint16(u[14:], 30)
+	binary.LittleEndian.PutUint16(u[16:], 0x2000)
+
+	effect := e.toFFEffect()
+	expected := FFPeriodicEffect{
+		Waveform:  ffSine,
+		Period:    100 * time.Millisecond,
+		Magnitude: 0x7fff,
+		Offset:    -2,
+		Phase:     90,
+		Envelope: FFEnvelope{
+			AttackLength: 20 * time.Millisecond,
+			AttackLevel:  0x1000,
+			FadeLength:   30 * time.Millisecond,
+			FadeLevel:    0x2000}}
+	if effect.Type != FFPeriodic || effect.Direction != 0x4000 || effect.Periodic != expected {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, effect.Periodic)
+	}
+}
+
+func TestConstantEffectDecoding(t *testing.T) {
+	e := ffEffect{Type: ffConstant}
+	u := (*[ffEffectUnionSize]byte)(unsafe.Pointer(&e.U))[:]
+	binary.LittleEndian.PutUint16(u[0:], 0x8000)
+
+	effect := e.toFFEffect()
+	if effect.Type != FFConstant || effect.Constant.Level != -0x8000 {
+		t.Fatalf("Expected constant effect with level %d, but got %+v", -0x8000, effect)
+	}
+}
+
+func TestForceFeedbackPlaybackEvents(t *testing.T) {
+	vg := &vFFGamepad{effects: make(chan FFEvent, 3), done: make(chan struct{})}
+
+	vg.handleEvent(inputEvent{Type: EvFF, Code: 3, Value: 1})
+	vg.handleEvent(inputEvent{Type: EvFF, Code: 3, Value: 0})
+	vg.handleEvent(inputEvent{Type: EvFF, Code: ffGain, Value: 0x8000})
+
+	for _, expected := range []FFEvent{
+		{Kind: FFPlay, EffectID: 3, Value: 1},
+		{Kind: FFStop, EffectID: 3},
+		{Kind: FFSetGain, Value: 0x8000},
+	} {
+		actual := <-vg.effects
+		if actual != expected {
+			t.Fatalf("Expected: %+v\nActual: %+v", expected, actual)
+		}
+	}
+}
+
+func TestForceFeedbackDeliveryStopsOnClose(t *testing.T) {
+	vg := &vFFGamepad{effects: make(chan FFEvent), done: make(chan struct{})}
+	close(vg.done)
+
+	// must not block, since nobody is receiving from the effects channel
+	vg.handleEvent(inputEvent{Type: EvFF, Code: 0, Value: 1})
+}
@@ -0,0 +1,64 @@
+package uinput
+
+import (
+	"encoding/binary"
+	"io"
+	"unsafe"
+)
+
+// inputEventSize is the size of a single event as expected by the kernel (24 bytes on 64 bit platforms).
+const inputEventSize = int(unsafe.Sizeof(inputEvent{}))
+
+// frame collects all events that belong to a single logical action (e.g. moving a stick along both axes) including
+// the final sync event, so that they can be written to the device using a single write call. The underlying buffer
+// is reused, so that no allocations are necessary once the frame has grown to its final size.
+type frame struct {
+	buf []byte
+}
+
+// add appends a single event to the frame.
+func (f *frame) add(evType uint16, code uint16, value int32) {
+	n := len(f.buf)
+	if cap(f.buf)-n < inputEventSize {
+		grown := make([]byte, n, 2*cap(f.buf)+inputEventSize)
+		copy(grown, f.buf)
+		f.buf = grown
+	}
+	f.buf = f.buf[:n+inputEventSize]
+	putInputEvent(f.buf[n:], evType, code, value)
+}
+
+// writeTo appends the sync event and writes the whole frame at once. The frame is empty afterwards, regardless of
+// whether the write succeeded.
+func (f *frame) writeTo(w io.Writer) error {
+	defer f.reset()
+	f.add(EvSyn, SynReport, 0)
+	_, err := w.Write(f.buf)
+	return err
+}
+
+func (f *frame) reset() {
+	f.buf = f.buf[:0]
+}
+
+// putInputEvent encodes a single event into buf, which needs to hold at least inputEventSize bytes. The time is
+// left empty, since the kernel sets the time of injected events itself.
+func putInputEvent(buf []byte, evType uint16, code uint16, value int32) {
+	timeSize := inputEventSize - 8
+	for i := 0; i < timeSize; i++ {
+		buf[i] = 0
+	}
+	binary.LittleEndian.PutUint16(buf[timeSize:], evType)
+	binary.LittleEndian.PutUint16(buf[timeSize+2:], code)
+	binary.LittleEndian.PutUint32(buf[timeSize+4:], uint32(value))
+}
+
+// inputEventFromBuffer decodes a single event that has been read from the device. Only type, code and value are
+// decoded, since the time of events sent to the device is irrelevant.
+func inputEventFromBuffer(buf []byte) inputEvent {
+	timeSize := inputEventSize - 8
+	return inputEvent{
+		Type:  binary.LittleEndian.Uint16(buf[timeSize:]),
+		Code:  binary.LittleEndian.Uint16(buf[timeSize+2:]),
+		Value: int32(binary.LittleEndian.Uint32(buf[timeSize+4:]))}
+}
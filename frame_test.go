@@ -0,0 +1,152 @@
+package uinput
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"testing"
+)
+
+// countingWriter records every single write call.
+type countingWriter struct {
+	writes [][]byte
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes = append(w.writes, append([]byte(nil), p...))
+	return len(p), nil
+}
+
+func TestFrameIsWrittenAtOnce(t *testing.T) {
+	var f frame
+	w := &countingWriter{}
+
+	f.add(EvAbs, AbsX, 42)
+	f.add(EvAbs, AbsY, 42)
+	err := f.writeTo(w)
+	if err != nil {
+		t.Fatalf("Failed to write frame. Last error was: %s\n", err)
+	}
+
+	if len(w.writes) != 1 {
+		t.Fatalf("Expected a single write, but got %d", len(w.writes))
+	}
+	events := decodeEvents(t, w.writes[0])
+	expected := []inputEvent{
+		{Type: EvAbs, Code: AbsX, Value: 42},
+		{Type: EvAbs, Code: AbsY, Value: 42},
+		{Type: EvSyn, Code: SynReport, Value: 0},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, but got %d", len(expected), len(events))
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Fatalf("Expected: %+v\nActual: %+v", expected[i], events[i])
+		}
+	}
+}
+
+func TestFrameIsEmptyAfterWrite(t *testing.T) {
+	var f frame
+	w := &countingWriter{}
+
+	f.add(EvKey, KeyA, 1)
+	_ = f.writeTo(w)
+	f.add(EvKey, KeyA, 0)
+	_ = f.writeTo(w)
+
+	if len(w.writes) != 2 {
+		t.Fatalf("Expected two writes, but got %d", len(w.writes))
+	}
+	if events := decodeEvents(t, w.writes[1]); len(events) != 2 || events[0].Value != 0 {
+		t.Fatalf("Expected second frame to only contain the key release, but got %+v", events)
+	}
+}
+
+func BenchmarkStickMovePerEventWrites(b *testing.B) {
+	devNull := openDevNull(b)
+	defer devNull.Close()
+
+	writes := 0
+	buf := make([]byte, inputEventSize)
+	for i := 0; i < b.N; i++ {
+		for _, iev := range []inputEvent{
+			{Type: EvAbs, Code: AbsX, Value: 100},
+			{Type: EvAbs, Code: AbsY, Value: 100},
+			{Type: EvSyn, Code: SynReport, Value: 0},
+		} {
+			putInputEvent(buf, iev.Type, iev.Code, iev.Value)
+			_, err := devNull.Write(buf)
+			if err != nil {
+				b.Fatal(err)
+			}
+			writes++
+		}
+	}
+	b.ReportMetric(float64(writes)/float64(b.N), "writes/op")
+}
+
+func BenchmarkStickMoveFrameWrite(b *testing.B) {
+	devNull := openDevNull(b)
+	defer devNull.Close()
+
+	w := &countingFile{file: devNull}
+	var f frame
+	for i := 0; i < b.N; i++ {
+		f.add(EvAbs, AbsX, 100)
+		f.add(EvAbs, AbsY, 100)
+		err := f.writeTo(w)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(w.writes)/float64(b.N), "writes/op")
+}
+
+// countingFile counts the writes to the underlying file.
+type countingFile struct {
+	file   *os.File
+	writes int
+}
+
+func (c *countingFile) Write(p []byte) (int, error) {
+	c.writes++
+	return c.file.Write(p)
+}
+
+func openDevNull(b *testing.B) *os.File {
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		b.Fatalf("Failed to open %s: %v", os.DevNull, err)
+	}
+	return devNull
+}
+
+func decodeEvents(t *testing.T, buf []byte) []inputEvent {
+	var events []inputEvent
+	r := bytes.NewReader(buf)
+	for r.Len() > 0 {
+		var iev inputEvent
+		err := binary.Read(r, binary.LittleEndian, &iev)
+		if err != nil {
+			t.Fatalf("Failed to decode event: %v", err)
+		}
+		events = append(events, iev)
+	}
+	return events
+}
+
+func TestInputEventEncodingMatchesKernelLayout(t *testing.T) {
+	iev := inputEvent{Type: EvAbs, Code: AbsMtPositionX, Value: -2}
+	buf := make([]byte, inputEventSize)
+	putInputEvent(buf, iev.Type, iev.Code, iev.Value)
+
+	expected := payload(t, iev)
+	if !bytes.Equal(expected, buf) {
+		t.Fatalf("Expected: %x\nActual: %x", expected, buf)
+	}
+	if decoded := inputEventFromBuffer(buf); decoded != iev {
+		t.Fatalf("Expected: %+v\nActual: %+v", iev, decoded)
+	}
+}
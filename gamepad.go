@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"os"
 )
 
 const MaximumAxisValue = 32767
@@ -61,18 +60,33 @@ type Gamepad interface {
 	// HatRelease will issue a hat-release event in the given direction
 	HatRelease(direction HatDirection) error
 
+	// ReleaseAll will release all buttons that are held down and re-center the sticks and the hat. This is done
+	// automatically when the device is closed.
+	ReleaseAll() error
+
+	// Info will return information about the device as seen by the kernel, such as its syspath and event node.
+	Info() (DeviceInfo, error)
+
+	// WriteEvents will write the given events followed by a sync event (SYN_REPORT) as a single frame. This allows to
+	// send events that are not covered by the other methods. All events need to refer to codes that have been
+	// registered for the device, sync events are not allowed.
+	WriteEvents(events ...Event) error
+
 	io.Closer
 }
 
+// gamepadRestAxes are the axes that are re-centered when all buttons are released.
+var gamepadRestAxes = []uint16{AbsX, AbsY, AbsRX, AbsRY, AbsHat0X, AbsHat0Y}
+
 type vGamepad struct {
-	name       []byte
-	deviceFile *os.File
+	name []byte
+	dev  *uinputDevice
 }
 
 // CreateGamepad will create a new gamepad using the given uinput
 // device path of the uinput device.
-func CreateGamepad(path string, name []byte, vendor uint16, product uint16) (Gamepad, error) { // TODO: Consider moving this to a generic function that works for all devices
-	err := validateDevicePath(path)
+func CreateGamepad(path string, name []byte, vendor uint16, product uint16, opts ...Option) (Gamepad, error) { // TODO: Consider moving this to a generic function that works for all devices
+	err := validateDevicePath(path, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -81,12 +95,12 @@ func CreateGamepad(path string, name []byte, vendor uint16, product uint16) (Gam
 		return nil, err
 	}
 
-	fd, err := createVGamepadDevice(path, name, vendor, product)
+	fd, err := createVGamepadDevice(path, name, vendor, product, 0, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	return vGamepad{name: name, deviceFile: fd}, nil
+	return vGamepad{name: name, dev: newUinputDevice(fd, gamepadCapabilities(), gamepadRestAxes...)}, nil
 }
 
 func (vg vGamepad) ButtonPress(key int) error {
@@ -102,43 +116,35 @@ func (vg vGamepad) ButtonPress(key int) error {
 }
 
 func (vg vGamepad) ButtonDown(key int) error {
-	return sendBtnEvent(vg.deviceFile, []int{key}, btnStatePressed)
+	return sendBtnEvent(vg.dev, key, btnStatePressed)
 }
 
 func (vg vGamepad) ButtonUp(key int) error {
-	return sendBtnEvent(vg.deviceFile, []int{key}, btnStateReleased)
+	return sendBtnEvent(vg.dev, key, btnStateReleased)
 }
 
 func (vg vGamepad) LeftStickMoveX(value float32) error {
-	return vg.sendStickAxisEvent(absX, value)
+	return vg.sendStickAxisEvent(AbsX, value)
 }
 
 func (vg vGamepad) LeftStickMoveY(value float32) error {
-	return vg.sendStickAxisEvent(absY, value)
+	return vg.sendStickAxisEvent(AbsY, value)
 }
 
 func (vg vGamepad) RightStickMoveX(value float32) error {
-	return vg.sendStickAxisEvent(absRX, value)
+	return vg.sendStickAxisEvent(AbsRX, value)
 }
 
 func (vg vGamepad) RightStickMoveY(value float32) error {
-	return vg.sendStickAxisEvent(absRY, value)
+	return vg.sendStickAxisEvent(AbsRY, value)
 }
 
 func (vg vGamepad) RightStickMove(x, y float32) error {
-	values := map[uint16]float32{}
-	values[absRX] = x
-	values[absRY] = y
-
-	return vg.sendStickEvent(values)
+	return vg.sendStickEvent(AbsRX, x, AbsRY, y)
 }
 
 func (vg vGamepad) LeftStickMove(x, y float32) error {
-	values := map[uint16]float32{}
-	values[absX] = x
-	values[absY] = y
-
-	return vg.sendStickEvent(values)
+	return vg.sendStickEvent(AbsX, x, AbsY, y)
 }
 
 func (vg vGamepad) HatPress(direction HatDirection) error {
@@ -150,45 +156,22 @@ func (vg vGamepad) HatRelease(direction HatDirection) error {
 }
 
 func (vg vGamepad) sendStickAxisEvent(absCode uint16, value float32) error {
-	ev := inputEvent{
-		Type:  evAbs,
-		Code:  absCode,
-		Value: denormalizeInput(value),
-	}
-
-	buf, err := inputEventToBuffer(ev)
-	if err != nil {
-		return fmt.Errorf("writing abs stick event failed: %v", err)
-	}
-
-	_, err = vg.deviceFile.Write(buf)
+	err := vg.dev.send(inputEvent{Type: EvAbs, Code: absCode, Value: denormalizeInput(value)})
 	if err != nil {
-		return fmt.Errorf("failed to write abs stick event to device file: %v", err)
+		return fmt.Errorf("failed to write abs stick event to device file: %w", err)
 	}
-
-	return syncEvents(vg.deviceFile)
+	return nil
 }
 
-func (vg vGamepad) sendStickEvent(values map[uint16]float32) error {
-	for code, value := range values {
-		ev := inputEvent{
-			Type:  evAbs,
-			Code:  code,
-			Value: denormalizeInput(value),
-		}
-
-		buf, err := inputEventToBuffer(ev)
-		if err != nil {
-			return fmt.Errorf("writing abs stick event failed: %v", err)
-		}
-
-		_, err = vg.deviceFile.Write(buf)
-		if err != nil {
-			return fmt.Errorf("failed to write abs stick event to device file: %v", err)
-		}
+// sendStickEvent moves a stick along both of its axes within the same frame.
+func (vg vGamepad) sendStickEvent(xCode uint16, x float32, yCode uint16, y float32) error {
+	err := vg.dev.send(
+		inputEvent{Type: EvAbs, Code: xCode, Value: denormalizeInput(x)},
+		inputEvent{Type: EvAbs, Code: yCode, Value: denormalizeInput(y)})
+	if err != nil {
+		return fmt.Errorf("failed to write abs stick event to device file: %w", err)
 	}
-
-	return syncEvents(vg.deviceFile)
+	return nil
 }
 
 func (vg vGamepad) sendHatEvent(direction HatDirection, action HatAction) error {
@@ -198,22 +181,22 @@ func (vg vGamepad) sendHatEvent(direction HatDirection, action HatAction) error
 	switch direction {
 	case HatUp:
 		{
-			event = absHat0Y
+			event = AbsHat0Y
 			value = -1
 		}
 	case HatDown:
 		{
-			event = absHat0Y
+			event = AbsHat0Y
 			value = 1
 		}
 	case HatLeft:
 		{
-			event = absHat0X
+			event = AbsHat0X
 			value = -1
 		}
 	case HatRight:
 		{
-			event = absHat0X
+			event = AbsHat0X
 			value = 1
 		}
 	default:
@@ -226,112 +209,141 @@ func (vg vGamepad) sendHatEvent(direction HatDirection, action HatAction) error
 		value = 0
 	}
 
-	ev := inputEvent{
-		Type:  evAbs,
-		Code:  event,
-		Value: value,
-	}
-
-	buf, err := inputEventToBuffer(ev)
+	err := vg.dev.send(inputEvent{Type: EvAbs, Code: event, Value: value})
 	if err != nil {
-		return fmt.Errorf("writing abs stick event failed: %v", err)
+		return fmt.Errorf("failed to write abs stick event to device file: %w", err)
 	}
+	return nil
+}
 
-	_, err = vg.deviceFile.Write(buf)
-	if err != nil {
-		return fmt.Errorf("failed to write abs stick event to device file: %v", err)
-	}
+// ReleaseAll will release everything that is currently held down, see the interface for details.
+func (vg vGamepad) ReleaseAll() error {
+	return vg.dev.releaseAll()
+}
 
-	return syncEvents(vg.deviceFile)
+// Info will return information about the device as seen by the kernel.
+func (vg vGamepad) Info() (DeviceInfo, error) {
+	return vg.dev.info()
+}
+
+// WriteEvents will write the given events as a single frame.
+func (vg vGamepad) WriteEvents(events ...Event) error {
+	return vg.dev.writeEvents(events)
 }
 
 func (vg vGamepad) Close() error {
-	return closeDevice(vg.deviceFile)
+	return vg.dev.close()
 }
 
-func createVGamepadDevice(path string, name []byte, vendor uint16, product uint16) (fd *os.File, err error) {
-	// This array is needed to register the event keys for the gamepad device.
-	keys := []uint16{
-		ButtonGamepad,
+// gamepadButtons are the buttons of a gamepad.
+var gamepadButtons = []int{
+	ButtonGamepad,
 
-		ButtonSouth,
-		ButtonEast,
-		ButtonNorth,
-		ButtonWest,
+	ButtonSouth,
+	ButtonEast,
+	ButtonNorth,
+	ButtonWest,
 
-		ButtonBumperLeft,
-		ButtonBumperRight,
-		ButtonTriggerLeft,
-		ButtonTriggerRight,
-		ButtonThumbLeft,
-		ButtonThumbRight,
+	ButtonBumperLeft,
+	ButtonBumperRight,
+	ButtonTriggerLeft,
+	ButtonTriggerRight,
+	ButtonThumbLeft,
+	ButtonThumbRight,
 
-		ButtonSelect,
-		ButtonStart,
+	ButtonSelect,
+	ButtonStart,
 
-		ButtonDpadUp,    // * * *
-		ButtonDpadDown,  // * These buttons can be used instead of the hat events.
-		ButtonDpadLeft,  // *
-		ButtonDpadRight, // * * *
+	ButtonDpadUp,    // * * *
+	ButtonDpadDown,  // * These buttons can be used instead of the hat events.
+	ButtonDpadLeft,  // *
+	ButtonDpadRight, // * * *
 
-		ButtonMode,
-	}
+	ButtonMode,
+}
 
-	// absEvents is for the absolute events for the gamepad device.
-	absEvents := []uint16{
-		absX,
-		absY,
-		absZ,
-		absRX,
-		absRY,
-		absRZ,
-		absHat0X,
-		absHat0Y,
-	}
+// gamepadAxes are the absolute axes of a gamepad.
+var gamepadAxes = []int{
+	AbsX,
+	AbsY,
+	AbsZ,
+	AbsRX,
+	AbsRY,
+	AbsRZ,
+	AbsHat0X,
+	AbsHat0Y,
+}
 
-	deviceFile, err := createDeviceFile(path)
+// gamepadCapabilities returns the codes registered by createVGamepadDevice (except for force feedback effects).
+func gamepadCapabilities() capabilities {
+	caps := make(capabilities)
+	caps.add(EvKey, gamepadButtons...)
+	caps.add(EvAbs, gamepadAxes...)
+	return caps
+}
+
+func createVGamepadDevice(path string, name []byte, vendor uint16, product uint16, effectsMax uint32, opts []Option) (fd backend, err error) {
+	deviceFile, err := createDeviceFile(path, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create virtual gamepad device: %v", err)
+		return nil, fmt.Errorf("failed to create virtual gamepad device: %w", err)
 	}
 
 	// register button events
-	err = registerDevice(deviceFile, uintptr(evKey))
+	err = registerDevice(deviceFile, uintptr(EvKey))
 	if err != nil {
-		_ = deviceFile.Close()
-		return nil, fmt.Errorf("failed to register virtual gamepad device: %v", err)
+		// registerDevice closes the device file on failure
+		return nil, fmt.Errorf("failed to register virtual gamepad device: %w", err)
 	}
 
-	for _, code := range keys {
+	for _, code := range gamepadButtons {
 		err = ioctl(deviceFile, uiSetKeyBit, uintptr(code))
 		if err != nil {
 			_ = deviceFile.Close()
-			return nil, fmt.Errorf("failed to register key number %d: %v", code, err)
+			return nil, fmt.Errorf("failed to register key number %d: %w", code, err)
 		}
 	}
 
 	// register absolute events
-	err = registerDevice(deviceFile, uintptr(evAbs))
+	err = registerDevice(deviceFile, uintptr(EvAbs))
 	if err != nil {
-		_ = deviceFile.Close()
-		return nil, fmt.Errorf("failed to register absolute event input device: %v", err)
+		// registerDevice closes the device file on failure
+		return nil, fmt.Errorf("failed to register absolute event input device: %w", err)
 	}
 
-	for _, event := range absEvents {
+	for _, event := range gamepadAxes {
 		err = ioctl(deviceFile, uiSetAbsBit, uintptr(event))
 		if err != nil {
 			_ = deviceFile.Close()
-			return nil, fmt.Errorf("failed to register absolute event %v: %v", event, err)
+			return nil, fmt.Errorf("failed to register absolute event %v: %w", event, err)
+		}
+	}
+
+	// register force feedback effects (only if requested)
+	if effectsMax > 0 {
+		err = registerDevice(deviceFile, uintptr(EvFF))
+		if err != nil {
+			// registerDevice closes the device file on failure
+			return nil, fmt.Errorf("failed to register force feedback device: %w", err)
+		}
+
+		for _, effect := range []uint16{ffRumble, ffPeriodic, ffConstant, ffSquare, ffTriangle, ffSine, ffSawUp, ffSawDown, ffGain} {
+			err = ioctl(deviceFile, uiSetFFBit, uintptr(effect))
+			if err != nil {
+				_ = deviceFile.Close()
+				return nil, fmt.Errorf("failed to register force feedback effect %v: %w", effect, err)
+			}
 		}
 	}
 
 	return createUsbDevice(deviceFile,
-		uinputUserDev{
-			Name: toUinputName(name),
-			ID: inputID{
+		deviceSetup{
+			name: name,
+			id: inputID{
 				Bustype: busUsb,
 				Vendor:  vendor,
 				Product: product,
-				Version: 1}})
+				Version: 1},
+			effectsMax: effectsMax}, opts)
 }
 
 // Takes in a normalized value (-1.0:1.0) and return an event value
@@ -1,9 +1,13 @@
 package uinput
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
+	"sync"
+	"syscall"
 	"testing"
 )
 
@@ -147,8 +151,8 @@ func TestGamepadCreationFailsOnEmptyPath(t *testing.T) {
 func TestGamepadCreationFailsOnNonExistentPathName(t *testing.T) {
 	path := "/some/bogus/path"
 	_, err := CreateGamepad(path, []byte("Gamepad"), 0xDEAD, 0xBEEF)
-	if !os.IsNotExist(err) {
-		t.Fatalf("Expected: os.IsNotExist error\nActual: %s", err)
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Expected: os.ErrNotExist error\nActual: %s", err)
 	}
 }
 
@@ -159,13 +163,27 @@ func TestGamepadCreationFailsOnWrongPathName(t *testing.T) {
 	}
 	defer file.Close()
 
-	expected := "failed to register virtual gamepad device: failed to close device: inappropriate ioctl for device"
+	expected := "failed to register virtual gamepad device: invalid file handle returned from ioctl: inappropriate ioctl for device (failed to close device: inappropriate ioctl for device)"
 	_, err = CreateGamepad(file.Name(), []byte("GamepadDevice"), 0xDEAD, 0xBEEF)
 	if err == nil || !(expected == err.Error()) {
 		t.Fatalf("Expected: %s\nActual: %s", expected, err)
 	}
 }
 
+func TestGamepadCreationClosesBackendOnceOnFailure(t *testing.T) {
+	for _, evType := range []uintptr{EvKey, EvAbs, EvFF} {
+		fake := NewFakeBackend()
+		fake.failRequest(uiSetEvBit, evType, syscall.EINVAL)
+		_, err := CreateForceFeedbackGamepad("/dev/uinput", []byte("Test Gamepad"), 0xDEAD, 0xBEEF, 4, WithFakeBackend(fake))
+		if err == nil {
+			t.Fatalf("Expected gamepad creation to fail if event type %d can not be registered", evType)
+		}
+		if fake.closeCalls != 1 {
+			t.Fatalf("Expected the backend to be closed once if event type %d can not be registered, but it was closed %d times", evType, fake.closeCalls)
+		}
+	}
+}
+
 func TestGamepadCreationFailsIfNameIsTooLong(t *testing.T) {
 	name := "adsfdsferqewoirueworiuejdsfjdfa;ljoewrjeworiewuoruew;rj;kdlfjoeai;jfewoaifjef;das"
 	expected := fmt.Sprintf("device name %s is too long (maximum of %d characters allowed)", name, uinputMaxNameSize)
@@ -246,3 +264,83 @@ func TestGamepadMoveToFailsOnClosedDevice(t *testing.T) {
 		t.Fatalf("Expected error due to closed device, but no error was returned.")
 	}
 }
+
+func BenchmarkGamepadLeftStickMove(b *testing.B) {
+	devNull := openDevNull(b)
+	defer devNull.Close()
+	vg := vGamepad{dev: newUinputDevice(&fileBackend{file: devNull}, gamepadCapabilities())}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		err := vg.LeftStickMove(0.5, -0.5)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestConcurrentGamepadActionsAreWrittenAsWholeFrames(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create pipe: %v", err)
+	}
+	defer r.Close()
+	vg := vGamepad{dev: newUinputDevice(&fileBackend{file: w}, gamepadCapabilities())}
+
+	frames := make(chan []inputEvent)
+	go func() {
+		defer close(frames)
+		buf := make([]byte, inputEventSize)
+		var events []inputEvent
+		for {
+			if _, err := io.ReadFull(r, buf); err != nil {
+				return
+			}
+			iev := inputEventFromBuffer(buf)
+			if iev.Type == EvSyn {
+				frames <- events
+				events = nil
+				continue
+			}
+			events = append(events, iev)
+		}
+	}()
+
+	const goroutines, actions = 16, 200
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			value := float32(g) / goroutines
+			for i := 0; i < actions; i++ {
+				if err := vg.LeftStickMove(value, value); err != nil {
+					t.Errorf("Failed to move stick: %v", err)
+					return
+				}
+				if err := vg.ButtonDown(ButtonSouth); err != nil {
+					t.Errorf("Failed to press button: %v", err)
+					return
+				}
+			}
+		}(g)
+	}
+	go func() {
+		wg.Wait()
+		_ = w.Close()
+	}()
+
+	count := 0
+	for frame := range frames {
+		count++
+		switch {
+		case len(frame) == 1 && frame[0].Type == EvKey && frame[0].Code == ButtonSouth:
+		case len(frame) == 2 && frame[0].Code == AbsX && frame[1].Code == AbsY && frame[0].Value == frame[1].Value:
+		default:
+			t.Fatalf("Frame %d has been corrupted by concurrent writes: %+v", count, frame)
+		}
+	}
+	if expected := 2 * goroutines * actions; count != expected {
+		t.Fatalf("Expected %d frames, but got %d", expected, count)
+	}
+}
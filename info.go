@@ -0,0 +1,189 @@
+package uinput
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// InputID identifies a device by its bus type, vendor, product and version.
+type InputID struct {
+	BusType BusType
+	Vendor  uint16
+	Product uint16
+	Version uint16
+}
+
+// Capabilities holds the codes that have been registered for each event type, as reported by the kernel.
+type Capabilities struct {
+	EventTypes []int
+	Keys       []int
+	RelAxes    []int
+	AbsAxes    []int
+	Misc       []int
+	LEDs       []int
+	Sounds     []int
+	FF         []int
+	Switches   []int
+	Properties []int
+}
+
+// DeviceInfo describes a created device as seen by the kernel. It allows to point other tools at the exact device.
+type DeviceInfo struct {
+	// Syspath is the path of the device in sysfs, for example /sys/devices/virtual/input/input42.
+	Syspath string
+
+	// EventNode is the path of the event device node, for example /dev/input/event7.
+	EventNode string
+
+	// Handlers lists all handlers attached to the device, for example event7, js0 or mouse3.
+	Handlers []string
+
+	Name         string
+	Phys         string
+	ID           InputID
+	Capabilities Capabilities
+}
+
+var handlerName = regexp.MustCompile(`^(event|js|mouse)[0-9]+$`)
+
+// readDeviceInfo collects the information about the device with the given syspath from sysfs. The event node is
+// expected to reside within devDir.
+func readDeviceInfo(syspath string, devDir string) (DeviceInfo, error) {
+	info := DeviceInfo{Syspath: syspath}
+
+	handlers, err := readHandlers(syspath)
+	if err != nil {
+		return DeviceInfo{}, err
+	}
+	info.Handlers = handlers
+	for _, handler := range handlers {
+		if strings.HasPrefix(handler, "event") {
+			info.EventNode = filepath.Join(devDir, handler)
+		}
+	}
+
+	info.Name, err = readAttribute(syspath, "name")
+	if err != nil {
+		return DeviceInfo{}, err
+	}
+	// phys is empty unless it has been set explicitly
+	info.Phys, _ = readAttribute(syspath, "phys")
+
+	ids := make([]uint16, 4)
+	for i, attribute := range []string{"bustype", "vendor", "product", "version"} {
+		ids[i], err = readHexAttribute(syspath, filepath.Join("id", attribute))
+		if err != nil {
+			return DeviceInfo{}, err
+		}
+	}
+	info.ID = InputID{BusType: BusType(ids[0]), Vendor: ids[1], Product: ids[2], Version: ids[3]}
+
+	for _, capability := range []struct {
+		attribute string
+		codes     *[]int
+	}{
+		{"capabilities/ev", &info.Capabilities.EventTypes},
+		{"capabilities/key", &info.Capabilities.Keys},
+		{"capabilities/rel", &info.Capabilities.RelAxes},
+		{"capabilities/abs", &info.Capabilities.AbsAxes},
+		{"capabilities/msc", &info.Capabilities.Misc},
+		{"capabilities/led", &info.Capabilities.LEDs},
+		{"capabilities/snd", &info.Capabilities.Sounds},
+		{"capabilities/ff", &info.Capabilities.FF},
+		{"capabilities/sw", &info.Capabilities.Switches},
+		{"properties", &info.Capabilities.Properties},
+	} {
+		bitmap, err := readAttribute(syspath, capability.attribute)
+		if err != nil {
+			return DeviceInfo{}, err
+		}
+		*capability.codes, err = parseBitmap(bitmap)
+		if err != nil {
+			return DeviceInfo{}, fmt.Errorf("failed to parse %s: %w", capability.attribute, err)
+		}
+	}
+	return info, nil
+}
+
+// readHandlers returns the names of all handlers of the device, which show up as subdirectories in sysfs.
+func readHandlers(syspath string) ([]string, error) {
+	entries, err := ioutil.ReadDir(syspath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read syspath: %w", err)
+	}
+	var handlers []string
+	for _, entry := range entries {
+		if handlerName.MatchString(entry.Name()) {
+			handlers = append(handlers, entry.Name())
+		}
+	}
+	sort.Strings(handlers)
+	return handlers, nil
+}
+
+func readAttribute(syspath string, attribute string) (string, error) {
+	value, err := ioutil.ReadFile(filepath.Join(syspath, attribute))
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", attribute, err)
+	}
+	return strings.TrimSpace(string(value)), nil
+}
+
+func readHexAttribute(syspath string, attribute string) (uint16, error) {
+	value, err := readAttribute(syspath, attribute)
+	if err != nil {
+		return 0, err
+	}
+	parsed, err := strconv.ParseUint(value, 16, 16)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s: %w", attribute, err)
+	}
+	return uint16(parsed), nil
+}
+
+// parseBitmap returns the codes of all bits set in a bitmap as printed by the kernel, which consists of hex words
+// the size of a long, starting with the most significant word.
+func parseBitmap(bitmap string) ([]int, error) {
+	wordBits := int(unsafe.Sizeof(uintptr(0))) * 8
+	words := strings.Fields(bitmap)
+	var codes []int
+	for i := len(words) - 1; i >= 0; i-- {
+		word, err := strconv.ParseUint(words[i], 16, wordBits)
+		if err != nil {
+			return nil, err
+		}
+		offset := (len(words) - 1 - i) * wordBits
+		for bit := 0; word != 0; bit++ {
+			if word&1 != 0 {
+				codes = append(codes, offset+bit)
+			}
+			word >>= 1
+		}
+	}
+	return codes, nil
+}
+
+// fetchSysname returns the name of the device's directory in sysfs (inputN).
+func fetchSysname(deviceFile backend) (string, error) {
+	// 64 for name + 1 for null byte
+	name := make([]byte, 65)
+	err := ioctlPtr(deviceFile, uiGetSysname, unsafe.Pointer(&name[0]))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(name), "\x00"), nil
+}
+
+func fetchSyspath(deviceFile backend) (string, error) {
+	sysname, err := fetchSysname(deviceFile)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(sysInputDir, sysname), nil
+}
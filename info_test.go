@@ -0,0 +1,125 @@
+package uinput
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+func TestParseBitmap(t *testing.T) {
+	bitmap := "3 0 80000000000000ff"
+	expected := []int{0, 1, 2, 3, 4, 5, 6, 7, 63, 128, 129}
+	if unsafe.Sizeof(uintptr(0)) == 4 {
+		bitmap = "3 0 80000000 ff"
+		expected = []int{0, 1, 2, 3, 4, 5, 6, 7, 63, 96, 97}
+	}
+
+	codes, err := parseBitmap(bitmap)
+	if err != nil {
+		t.Fatalf("Failed to parse bitmap: %v", err)
+	}
+	if !reflect.DeepEqual(expected, codes) {
+		t.Fatalf("Expected: %v\nActual: %v", expected, codes)
+	}
+}
+
+func TestParseEmptyBitmap(t *testing.T) {
+	codes, err := parseBitmap("0")
+	if err != nil || len(codes) != 0 {
+		t.Fatalf("Expected no codes, but got %v (error: %v)", codes, err)
+	}
+}
+
+func TestReadDeviceInfo(t *testing.T) {
+	syspath, err := ioutil.TempDir("", "uinput-info-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create directory: %v", err)
+	}
+	defer os.RemoveAll(syspath)
+
+	for _, dir := range []string{"event7", "js0", "power", "id", "capabilities"} {
+		if err := os.Mkdir(filepath.Join(syspath, dir), 0755); err != nil {
+			t.Fatalf("Failed to setup test. Unable to create directory: %v", err)
+		}
+	}
+	for attribute, value := range map[string]string{
+		"name":             "Test Gamepad",
+		"phys":             "",
+		"properties":       "0",
+		"id/bustype":       "0003",
+		"id/vendor":        "dead",
+		"id/product":       "beef",
+		"id/version":       "0001",
+		"capabilities/ev":  "b",
+		"capabilities/key": "3",
+		"capabilities/rel": "0",
+		"capabilities/abs": "30003",
+		"capabilities/msc": "0",
+		"capabilities/led": "0",
+		"capabilities/snd": "0",
+		"capabilities/ff":  "0",
+		"capabilities/sw":  "0",
+	} {
+		if err := ioutil.WriteFile(filepath.Join(syspath, attribute), []byte(value+"\n"), 0644); err != nil {
+			t.Fatalf("Failed to setup test. Unable to write %s: %v", attribute, err)
+		}
+	}
+
+	info, err := readDeviceInfo(syspath, "/dev/input")
+	if err != nil {
+		t.Fatalf("Failed to read device info: %v", err)
+	}
+	expected := DeviceInfo{
+		Syspath:   syspath,
+		EventNode: "/dev/input/event7",
+		Handlers:  []string{"event7", "js0"},
+		Name:      "Test Gamepad",
+		ID:        InputID{BusType: BusUSB, Vendor: 0xdead, Product: 0xbeef, Version: 1},
+		Capabilities: Capabilities{
+			EventTypes: []int{EvSyn, EvKey, EvAbs},
+			Keys:       []int{0, 1},
+			AbsAxes:    []int{AbsX, AbsY, AbsHat0X, AbsHat0Y},
+		},
+	}
+	if !reflect.DeepEqual(expected, info) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, info)
+	}
+}
+
+func TestGamepadInfo(t *testing.T) {
+	vg, err := CreateGamepad("/dev/uinput", []byte("Test Gamepad"), 0xDEAD, 0xBEEF)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+	defer vg.Close()
+
+	info, err := vg.Info()
+	if err != nil {
+		t.Fatalf("Failed to fetch device info. Last error was: %s\n", err)
+	}
+	if strings.ContainsRune(info.Syspath, 0) || !strings.HasPrefix(info.Syspath, "/sys/devices/virtual/input/input") {
+		t.Fatalf("Unexpected syspath: %q", info.Syspath)
+	}
+	if !strings.HasPrefix(info.EventNode, "/dev/input/event") {
+		t.Fatalf("Unexpected event node: %q", info.EventNode)
+	}
+	if info.Name != "Test Gamepad" || info.ID.Vendor != 0xDEAD || info.ID.Product != 0xBEEF {
+		t.Fatalf("Unexpected name or id: %q, %+v", info.Name, info.ID)
+	}
+	if !containsCode(info.Capabilities.Keys, ButtonSouth) || !containsCode(info.Capabilities.AbsAxes, AbsHat0X) {
+		t.Fatalf("Expected registered buttons and axes to be reported, but got: %+v", info.Capabilities)
+	}
+}
+
+func containsCode(codes []int, code int) bool {
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
@@ -3,7 +3,7 @@ package uinput
 import (
 	"fmt"
 	"io"
-	"os"
+	"sync"
 )
 
 // A Keyboard is an key event output device. It is used to
@@ -21,21 +21,67 @@ type Keyboard interface {
 	// The key can be any of the predefined keycodes from keycodes.go.
 	KeyUp(key int) error
 
+	// Type will type the given text using the keyboard layout of the device (see WithLayout), pressing the modifiers
+	// and dead keys each character requires. Characters that are not part of the layout are entered using the
+	// Unicode input methods of the device, if any (see WithUnicodeInput). Nothing is typed if the text contains a
+	// character that can not be entered, in which case an error matching ErrUnsupportedCharacter is returned.
+	Type(text string) error
+
+	// PressCombo will press each of the given key combos, one after another: the modifiers of a combo are pressed in
+	// order, the key is pressed and released, and the modifiers are released in reverse order. Use ParseCombo to
+	// create combos from strings like "ctrl+alt+t".
+	PressCombo(combos ...KeyCombo) error
+
 	// FetchSysPath will return the syspath to the device file.
 	FetchSyspath() (string, error)
 
+	// LEDState will return the current state of the keyboard LEDs (Caps Lock, Num Lock, ...) as set by the system.
+	LEDState() LEDState
+
+	// LEDChanges will return a channel that receives the new LED state whenever the system changes one of the
+	// keyboard LEDs. Only the latest state is kept if the channel is not drained. The channel will be closed once
+	// the device is closed.
+	LEDChanges() <-chan LEDState
+
+	// ReleaseAll will release all keys that are held down. This is done automatically when the device is closed.
+	ReleaseAll() error
+
+	// Info will return information about the device as seen by the kernel, such as its syspath and event node.
+	Info() (DeviceInfo, error)
+
+	// WriteEvents will write the given events followed by a sync event (SYN_REPORT) as a single frame. This allows to
+	// send events that are not covered by the other methods. All events need to refer to codes that have been
+	// registered for the device, sync events are not allowed.
+	WriteEvents(events ...Event) error
+
 	io.Closer
 }
 
+// LEDState holds the state of the keyboard LEDs. Note that the LEDs are controlled by the system (the compositor or
+// the console, for example), which will update them when a lock key is pressed on any of the attached keyboards.
+type LEDState struct {
+	NumLock    bool
+	CapsLock   bool
+	ScrollLock bool
+	Compose    bool
+	Kana       bool
+}
+
 type vKeyboard struct {
-	name       []byte
-	deviceFile *os.File
+	name          []byte
+	dev           *uinputDevice
+	layout        Layout
+	unicodeInputs []UnicodeInput
+
+	ledMutex   sync.Mutex
+	leds       LEDState
+	ledChanges chan LEDState
 }
 
 // CreateKeyboard will create a new keyboard using the given uinput
 // device path of the uinput device.
-func CreateKeyboard(path string, name []byte) (Keyboard, error) {
-	err := validateDevicePath(path)
+func CreateKeyboard(path string, name []byte, opts ...Option) (Keyboard, error) {
+	err := validateDevicePath(path, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -44,64 +90,228 @@ func CreateKeyboard(path string, name []byte) (Keyboard, error) {
 		return nil, err
 	}
 
-	fd, err := createVKeyboardDevice(path, name)
+	fd, err := createVKeyboardDevice(path, name, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	return vKeyboard{name: name, deviceFile: fd}, nil
+	options := newDeviceOptions(opts)
+	vk := &vKeyboard{
+		name:          name,
+		dev:           newUinputDevice(fd, keyboardCapabilities(options)),
+		layout:        options.layout,
+		unicodeInputs: options.unicodeInputs,
+		ledChanges:    make(chan LEDState, 1),
+	}
+	go func() {
+		readEvents(fd, vk.handleEvent)
+		close(vk.ledChanges)
+	}()
+
+	return vk, nil
 }
 
 // KeyPress will issue a single key press (push down a key and then immediately release it).
-func (vk vKeyboard) KeyPress(key int) error {
+func (vk *vKeyboard) KeyPress(key int) error {
 	if !keyCodeInRange(key) {
-		return fmt.Errorf("failed to perform KeyPress. Code %d is not in range", key)
+		return keyCodeError("failed to perform KeyPress. Code %d is not in range", key)
 	}
-	err := sendBtnEvent(vk.deviceFile, []int{key}, btnStatePressed)
+	err := sendBtnEvent(vk.dev, key, btnStatePressed)
 	if err != nil {
-		return fmt.Errorf("failed to issue the KeyDown event: %v", err)
+		return fmt.Errorf("failed to issue the KeyDown event: %w", err)
 	}
 
-	return sendBtnEvent(vk.deviceFile, []int{key}, btnStateReleased)
+	return sendBtnEvent(vk.dev, key, btnStateReleased)
 }
 
 // KeyDown will send the key code passed (see keycodes.go for available keycodes). Note that unless a key release
 // event is sent to the device, the key will remain pressed and therefore input will continuously be generated. Therefore,
 // do not forget to call "KeyUp" afterwards.
-func (vk vKeyboard) KeyDown(key int) error {
+func (vk *vKeyboard) KeyDown(key int) error {
 	if !keyCodeInRange(key) {
-		return fmt.Errorf("failed to perform KeyDown. Code %d is not in range", key)
+		return keyCodeError("failed to perform KeyDown. Code %d is not in range", key)
 	}
-	return sendBtnEvent(vk.deviceFile, []int{key}, btnStatePressed)
+	return sendBtnEvent(vk.dev, key, btnStatePressed)
 }
 
 // KeyUp will release the given key passed as a parameter (see keycodes.go for available keycodes). In most
 // cases it is recommended to call this function immediately after the "KeyDown" function in order to only issue a
 // single key press.
-func (vk vKeyboard) KeyUp(key int) error {
+func (vk *vKeyboard) KeyUp(key int) error {
 	if !keyCodeInRange(key) {
-		return fmt.Errorf("failed to perform KeyUp. Code %d is not in range", key)
+		return keyCodeError("failed to perform KeyUp. Code %d is not in range", key)
+	}
+
+	return sendBtnEvent(vk.dev, key, btnStateReleased)
+}
+
+// Type will type the given text, see the interface for details.
+func (vk *vKeyboard) Type(text string) error {
+	var combos []KeyCombo
+	for _, r := range text {
+		strokes, ok := vk.keystrokes(r)
+		if !ok {
+			return withSentinel(ErrUnsupportedCharacter,
+				fmt.Errorf("failed to type %q: character %q is not supported by layout %q", text, r, vk.layout.Name()))
+		}
+		for _, stroke := range strokes {
+			combo := keystrokeCombo(stroke)
+			err := validateCombo(combo)
+			if err != nil {
+				return err
+			}
+			combos = append(combos, combo)
+		}
+	}
+
+	for _, combo := range combos {
+		err := vk.pressCombo(combo)
+		if err != nil {
+			return fmt.Errorf("failed to type %q: %w", text, err)
+		}
+	}
+	return nil
+}
+
+// keystrokes returns the keystrokes that produce a character, using the layout of the keyboard or, if the layout does
+// not support the character, the first Unicode input method that is able to enter it.
+func (vk *vKeyboard) keystrokes(r rune) ([]Keystroke, bool) {
+	if strokes, ok := vk.layout.Keystrokes(r); ok {
+		return strokes, true
+	}
+	for _, input := range vk.unicodeInputs {
+		if strokes, ok := input.Keystrokes(r, vk.layout); ok {
+			return strokes, true
+		}
+	}
+	return nil, false
+}
+
+// modifierKeys maps modifiers to the keys that are held down for them.
+var modifierKeys = []struct {
+	modifier Modifier
+	key      int
+}{
+	{ModCtrl, KeyLeftctrl},
+	{ModShift, KeyLeftshift},
+	{ModAltGr, KeyRightalt},
+}
+
+// keystrokeCombo returns the combo that presses the key of a keystroke while holding down its modifiers.
+func keystrokeCombo(stroke Keystroke) KeyCombo {
+	combo := KeyCombo{Key: stroke.Key}
+	for _, m := range modifierKeys {
+		if stroke.Modifiers&m.modifier != 0 {
+			combo.Modifiers = append(combo.Modifiers, m.key)
+		}
+	}
+	return combo
+}
+
+// PressCombo will press the given key combos one after another, see the interface for details.
+func (vk *vKeyboard) PressCombo(combos ...KeyCombo) error {
+	for _, combo := range combos {
+		err := validateCombo(combo)
+		if err != nil {
+			return err
+		}
+	}
+	for _, combo := range combos {
+		err := vk.pressCombo(combo)
+		if err != nil {
+			return fmt.Errorf("failed to press key combo: %w", err)
+		}
+	}
+	return nil
+}
+
+func validateCombo(combo KeyCombo) error {
+	for _, modifier := range combo.Modifiers {
+		if !keyCodeInRange(modifier) {
+			return keyCodeError("failed to press key combo. Code %d is not in range", modifier)
+		}
+	}
+	if !keyCodeInRange(combo.Key) {
+		return keyCodeError("failed to press key combo. Code %d is not in range", combo.Key)
+	}
+	return nil
+}
+
+// pressCombo presses the modifiers of a combo in order, taps the key and releases the modifiers in reverse order.
+// Each key event is sent as a frame of its own, just like a physical keyboard does. The modifiers that have been
+// pressed are released even if sending an event fails.
+func (vk *vKeyboard) pressCombo(combo KeyCombo) error {
+	var err error
+	pressed := 0
+	for _, modifier := range combo.Modifiers {
+		err = sendBtnEvent(vk.dev, modifier, btnStatePressed)
+		if err != nil {
+			break
+		}
+		pressed++
+	}
+	if err == nil {
+		err = sendBtnEvent(vk.dev, combo.Key, btnStatePressed)
+		if err == nil {
+			err = sendBtnEvent(vk.dev, combo.Key, btnStateReleased)
+		}
 	}
+	for i := pressed - 1; i >= 0; i-- {
+		releaseErr := sendBtnEvent(vk.dev, combo.Modifiers[i], btnStateReleased)
+		if err == nil {
+			err = releaseErr
+		}
+	}
+	return err
+}
+
+// ReleaseAll will release everything that is currently held down, see the interface for details.
+func (vk *vKeyboard) ReleaseAll() error {
+	return vk.dev.releaseAll()
+}
+
+// Info will return information about the device as seen by the kernel.
+func (vk *vKeyboard) Info() (DeviceInfo, error) {
+	return vk.dev.info()
+}
 
-	return sendBtnEvent(vk.deviceFile, []int{key}, btnStateReleased)
+// WriteEvents will write the given events as a single frame.
+func (vk *vKeyboard) WriteEvents(events ...Event) error {
+	return vk.dev.writeEvents(events)
 }
 
 // Close will close the device and free resources.
 // It's usually a good idea to use defer to call this function.
-func (vk vKeyboard) Close() error {
-	return closeDevice(vk.deviceFile)
+func (vk *vKeyboard) Close() error {
+	return vk.dev.close()
+}
+
+// keyboardLEDs are the LEDs of a keyboard.
+var keyboardLEDs = []int{LedNumLock, LedCapsLock, LedScrollLock, LedCompose, LedKana}
+
+// keyboardCapabilities returns the codes registered by createVKeyboardDevice.
+func keyboardCapabilities(options deviceOptions) capabilities {
+	caps := make(capabilities)
+	for i := 0; i <= keyMax; i++ {
+		caps.add(EvKey, i)
+	}
+	caps.add(EvLed, keyboardLEDs...)
+	if options.scanCodes {
+		caps.add(EvMsc, MscScan)
+	}
+	return caps
 }
 
-func createVKeyboardDevice(path string, name []byte) (fd *os.File, err error) {
-	deviceFile, err := createDeviceFile(path)
+func createVKeyboardDevice(path string, name []byte, opts []Option) (fd backend, err error) {
+	deviceFile, err := createDeviceFile(path, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create virtual keyboard device: %v", err)
+		return nil, fmt.Errorf("failed to create virtual keyboard device: %w", err)
 	}
 
-	err = registerDevice(deviceFile, uintptr(evKey))
+	err = registerDevice(deviceFile, uintptr(EvKey))
 	if err != nil {
-		deviceFile.Close()
-		return nil, fmt.Errorf("failed to register virtual keyboard device: %v", err)
+		// registerDevice closes the device file on failure
+		return nil, fmt.Errorf("failed to register virtual keyboard device: %w", err)
 	}
 
 	// register key events
@@ -109,24 +319,104 @@ func createVKeyboardDevice(path string, name []byte) (fd *os.File, err error) {
 		err = ioctl(deviceFile, uiSetKeyBit, uintptr(i))
 		if err != nil {
 			deviceFile.Close()
-			return nil, fmt.Errorf("failed to register key number %d: %v", i, err)
+			return nil, fmt.Errorf("failed to register key number %d: %w", i, err)
+		}
+	}
+
+	err = registerDevice(deviceFile, uintptr(EvLed))
+	if err != nil {
+		// registerDevice closes the device file on failure
+		return nil, fmt.Errorf("failed to register keyboard leds: %w", err)
+	}
+
+	// register led events (the system will report lock key states through these)
+	for _, led := range keyboardLEDs {
+		err = ioctl(deviceFile, uiSetLedBit, uintptr(led))
+		if err != nil {
+			deviceFile.Close()
+			return nil, fmt.Errorf("failed to register led %d: %w", led, err)
+		}
+	}
+
+	// register scan codes, which may be sent along with key events using WriteEvents
+	if newDeviceOptions(opts).scanCodes {
+		err = registerDevice(deviceFile, uintptr(EvMsc))
+		if err != nil {
+			// registerDevice closes the device file on failure
+			return nil, fmt.Errorf("failed to register keyboard scan codes: %w", err)
+		}
+		err = ioctl(deviceFile, uiSetMscBit, uintptr(MscScan))
+		if err != nil {
+			deviceFile.Close()
+			return nil, fmt.Errorf("failed to register scan code event: %w", err)
 		}
 	}
 
 	return createUsbDevice(deviceFile,
-		uinputUserDev{
-			Name: toUinputName(name),
-			ID: inputID{
+		deviceSetup{
+			name: name,
+			id: inputID{
 				Bustype: busUsb,
 				Vendor:  0x4711,
 				Product: 0x0815,
-				Version: 1}})
+				Version: 1}}, opts)
 }
 
 func keyCodeInRange(key int) bool {
 	return key >= keyReserved && key <= keyMax
 }
 
-func (vk vKeyboard) FetchSyspath() (string, error) {
-	return fetchSyspath(vk.deviceFile)
+func (vk *vKeyboard) FetchSyspath() (string, error) {
+	return vk.dev.fetchSyspath()
+}
+
+// LEDState will return the current state of the keyboard LEDs.
+func (vk *vKeyboard) LEDState() LEDState {
+	vk.ledMutex.Lock()
+	defer vk.ledMutex.Unlock()
+	return vk.leds
+}
+
+// LEDChanges will return a channel that receives the new LED state whenever it changes.
+func (vk *vKeyboard) LEDChanges() <-chan LEDState {
+	return vk.ledChanges
+}
+
+func (vk *vKeyboard) handleEvent(iev inputEvent) {
+	if iev.Type != EvLed {
+		return
+	}
+
+	vk.ledMutex.Lock()
+	leds := vk.leds
+	on := iev.Value != 0
+	switch iev.Code {
+	case LedNumLock:
+		leds.NumLock = on
+	case LedCapsLock:
+		leds.CapsLock = on
+	case LedScrollLock:
+		leds.ScrollLock = on
+	case LedCompose:
+		leds.Compose = on
+	case LedKana:
+		leds.Kana = on
+	}
+	changed := leds != vk.leds
+	vk.leds = leds
+	vk.ledMutex.Unlock()
+
+	if !changed {
+		return
+	}
+	// replace a state that has not been received yet, so that the channel always holds the latest state
+	select {
+	case vk.ledChanges <- leds:
+	default:
+		select {
+		case <-vk.ledChanges:
+		default:
+		}
+		vk.ledChanges <- leds
+	}
 }
@@ -1,10 +1,13 @@
 package uinput
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"reflect"
 	"testing"
+	"time"
 )
 
 // This test will confirm that basic key events are working.
@@ -53,8 +56,8 @@ func TestKeyboardCreationFailsOnEmptyPath(t *testing.T) {
 func TestKeyboardCreationFailsOnNonExistentPathName(t *testing.T) {
 	path := "/some/bogus/path"
 	_, err := CreateKeyboard(path, []byte("KeyboardDevice"))
-	if !os.IsNotExist(err) {
-		t.Fatalf("Expected: os.IsNotExist error\nActual: %s", err)
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Expected: os.ErrNotExist error\nActual: %s", err)
 	}
 }
 
@@ -65,7 +68,7 @@ func TestKeyboardCreationFailsOnWrongPathName(t *testing.T) {
 	}
 	defer file.Close()
 
-	expected := "failed to register virtual keyboard device: failed to close device: inappropriate ioctl for device"
+	expected := "failed to register virtual keyboard device: invalid file handle returned from ioctl: inappropriate ioctl for device (failed to close device: inappropriate ioctl for device)"
 	_, err = CreateKeyboard(file.Name(), []byte("DialDevice"))
 	if err == nil || !(expected == err.Error()) {
 		t.Fatalf("Expected: %s\nActual: %s", expected, err)
@@ -192,3 +195,191 @@ func TestKeyboardSyspath(t *testing.T) {
 	}
 	t.Logf("Syspath: %s", sysPath)
 }
+
+func TestKeyboardLEDChangesChannelIsClosedOnClose(t *testing.T) {
+	vk, err := CreateKeyboard("/dev/uinput", []byte("Test Basic Keyboard"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+
+	err = vk.Close()
+	if err != nil {
+		t.Fatalf("Failed to close device. Last error was: %s\n", err)
+	}
+
+	// the channel may still hold the initial state reported by the system
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-vk.LEDChanges():
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("Expected led channel to be closed after closing the device")
+		}
+	}
+}
+
+func TestKeyboardLEDStateFollowsLEDEvents(t *testing.T) {
+	vk := &vKeyboard{ledChanges: make(chan LEDState, 1)}
+
+	vk.handleEvent(inputEvent{Type: EvLed, Code: LedCapsLock, Value: 1})
+	expected := LEDState{CapsLock: true}
+	if vk.LEDState() != expected {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, vk.LEDState())
+	}
+	if actual := <-vk.LEDChanges(); actual != expected {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, actual)
+	}
+
+	// events that are not led events must be ignored
+	vk.handleEvent(inputEvent{Type: EvKey, Code: LedNumLock, Value: 1})
+	if vk.LEDState() != expected {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, vk.LEDState())
+	}
+}
+
+func TestKeyboardLEDChangesKeepsLatestState(t *testing.T) {
+	vk := &vKeyboard{ledChanges: make(chan LEDState, 1)}
+
+	vk.handleEvent(inputEvent{Type: EvLed, Code: LedNumLock, Value: 1})
+	vk.handleEvent(inputEvent{Type: EvLed, Code: LedScrollLock, Value: 1})
+	// unchanged state must not trigger a notification
+	vk.handleEvent(inputEvent{Type: EvLed, Code: LedScrollLock, Value: 1})
+
+	expected := LEDState{NumLock: true, ScrollLock: true}
+	if actual := <-vk.LEDChanges(); actual != expected {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, actual)
+	}
+	select {
+	case actual := <-vk.LEDChanges():
+		t.Fatalf("Expected no further notification, but got %+v", actual)
+	default:
+	}
+}
+
+func BenchmarkKeyPress(b *testing.B) {
+	devNull := openDevNull(b)
+	defer devNull.Close()
+	vk := &vKeyboard{dev: newUinputDevice(&fileBackend{file: devNull}, keyboardCapabilities(newDeviceOptions(nil)))}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		err := vk.KeyPress(KeyA)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestKeyboardCloseIsIdempotent(t *testing.T) {
+	vk, err := CreateKeyboard("/dev/uinput", []byte("Test Keyboard"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+
+	err = vk.Close()
+	if err != nil {
+		t.Fatalf("Failed to close device. Last error was: %s\n", err)
+	}
+	err = vk.Close()
+	if err != nil {
+		t.Fatalf("Expected second close to succeed. Last error was: %s\n", err)
+	}
+
+	err = vk.KeyPress(KeyA)
+	if !errors.Is(err, ErrDeviceClosed) {
+		t.Fatalf("Expected key press on closed device to fail with ErrDeviceClosed, but got: %v", err)
+	}
+}
+
+func TestKeyboardWritesScanCodesAlongWithKeys(t *testing.T) {
+	fake := NewFakeBackend()
+	vk, err := CreateKeyboard("/dev/uinput", []byte("Test Keyboard"), WithFakeBackend(fake), WithScanCodes())
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	defer vk.Close()
+
+	err = vk.WriteEvents(
+		Event{Time: time.Now(), Type: EvMsc, Code: MscScan, Value: 0x70004},
+		Event{Time: time.Now(), Type: EvKey, Code: KeyA, Value: 1})
+	if err != nil {
+		t.Fatalf("Failed to write events. Last error was: %s\n", err)
+	}
+	err = vk.WriteEvents(Event{Type: EvRel, Code: RelX, Value: 1})
+	if !errors.Is(err, ErrAxisOutOfRange) {
+		t.Fatalf("Expected events of unregistered types to be rejected, but got: %v", err)
+	}
+
+	expected := []Event{{Type: EvMsc, Code: MscScan, Value: 0x70004}, {Type: EvKey, Code: KeyA, Value: 1}, {Type: EvSyn, Code: SynReport}}
+	if actual := fake.Events(); !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, actual)
+	}
+}
+
+func TestKeyboardRegistersScanCodesOnlyIfRequested(t *testing.T) {
+	fake := NewFakeBackend()
+	vk, err := CreateKeyboard("/dev/uinput", []byte("Test Keyboard"), WithFakeBackend(fake))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	defer vk.Close()
+
+	for _, request := range fake.Ioctls() {
+		if request.Request == uiSetEvBit && request.Arg == EvMsc {
+			t.Fatalf("Expected scan codes not to be registered by default")
+		}
+	}
+	err = vk.WriteEvents(Event{Type: EvMsc, Code: MscScan, Value: 0x70004})
+	if err == nil {
+		t.Fatalf("Expected scan codes to be rejected by default")
+	}
+}
+
+func TestKeyboardTypesTextUsingTheLayout(t *testing.T) {
+	fake := NewFakeBackend()
+	vk, err := CreateKeyboard("/dev/uinput", []byte("Test Keyboard"), WithFakeBackend(fake), WithLayout(LayoutGerman))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	defer vk.Close()
+
+	err = vk.Type("Zé@")
+	if err != nil {
+		t.Fatalf("Failed to type text. Last error was: %s\n", err)
+	}
+
+	var expected []Event
+	for _, key := range []struct {
+		code  uint16
+		value int32
+	}{
+		{KeyLeftshift, 1}, {KeyY, 1}, {KeyY, 0}, {KeyLeftshift, 0},
+		{KeyEqual, 1}, {KeyEqual, 0}, {KeyE, 1}, {KeyE, 0},
+		{KeyRightalt, 1}, {KeyQ, 1}, {KeyQ, 0}, {KeyRightalt, 0},
+	} {
+		expected = append(expected, Event{Type: EvKey, Code: key.code, Value: key.value}, Event{Type: EvSyn, Code: SynReport})
+	}
+	if actual := fake.Events(); !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, actual)
+	}
+}
+
+func TestKeyboardTypeFailsOnUnsupportedCharacter(t *testing.T) {
+	fake := NewFakeBackend()
+	vk, err := CreateKeyboard("/dev/uinput", []byte("Test Keyboard"), WithFakeBackend(fake))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	defer vk.Close()
+
+	err = vk.Type("Grüße")
+	if !errors.Is(err, ErrUnsupportedCharacter) {
+		t.Fatalf("Expected typing to fail with ErrUnsupportedCharacter, but got: %v", err)
+	}
+	if events := fake.Events(); len(events) != 0 {
+		t.Fatalf("Expected nothing to be typed, but got %+v", events)
+	}
+}
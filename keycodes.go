@@ -248,6 +248,15 @@ const (
 	KeyMicmute          = 248 /*Mute/UnmuteTheMicrophone*/
 	keyMax              = 248 // highest key currently defined in this keyboard api
 
+	ButtonLeft    = 0x110
+	ButtonRight   = 0x111
+	ButtonMiddle  = 0x112
+	ButtonSide    = 0x113
+	ButtonExtra   = 0x114
+	ButtonForward = 0x115
+	ButtonBack    = 0x116
+	ButtonTask    = 0x117
+
 	ButtonGamepad = 0x130
 
 	ButtonSouth = 0x130 // A / X
@@ -271,4 +280,14 @@ const (
 	ButtonDpadRight = 0x223
 
 	ButtonMode = 0x13c // This is the special button that usually bears the Xbox or Playstation logo
+
+	ButtonToolPen       = 0x140
+	ButtonToolRubber    = 0x141
+	ButtonToolFinger    = 0x145
+	ButtonTouch         = 0x14a
+	ButtonStylus        = 0x14b
+	ButtonStylus2       = 0x14c
+	ButtonToolDoubletap = 0x14d
+	ButtonToolTripletap = 0x14e
+	ButtonToolQuadtap   = 0x14f
 )
@@ -0,0 +1,344 @@
+package uinput
+
+import "strings"
+
+// keyNameTable lists the name of every key and button code as defined in input-event-codes.h, along with the name of
+// the respective constant in keycodes.go. If a code has more than one name, the first one is its primary name.
+var keyNameTable = []struct {
+	code     int
+	name     string
+	constant string
+}{
+	{KeyEsc, "KEY_ESC", "KeyEsc"},
+	{Key1, "KEY_1", "Key1"},
+	{Key2, "KEY_2", "Key2"},
+	{Key3, "KEY_3", "Key3"},
+	{Key4, "KEY_4", "Key4"},
+	{Key5, "KEY_5", "Key5"},
+	{Key6, "KEY_6", "Key6"},
+	{Key7, "KEY_7", "Key7"},
+	{Key8, "KEY_8", "Key8"},
+	{Key9, "KEY_9", "Key9"},
+	{Key0, "KEY_0", "Key0"},
+	{KeyMinus, "KEY_MINUS", "KeyMinus"},
+	{KeyEqual, "KEY_EQUAL", "KeyEqual"},
+	{KeyBackspace, "KEY_BACKSPACE", "KeyBackspace"},
+	{KeyTab, "KEY_TAB", "KeyTab"},
+	{KeyQ, "KEY_Q", "KeyQ"},
+	{KeyW, "KEY_W", "KeyW"},
+	{KeyE, "KEY_E", "KeyE"},
+	{KeyR, "KEY_R", "KeyR"},
+	{KeyT, "KEY_T", "KeyT"},
+	{KeyY, "KEY_Y", "KeyY"},
+	{KeyU, "KEY_U", "KeyU"},
+	{KeyI, "KEY_I", "KeyI"},
+	{KeyO, "KEY_O", "KeyO"},
+	{KeyP, "KEY_P", "KeyP"},
+	{KeyLeftbrace, "KEY_LEFTBRACE", "KeyLeftbrace"},
+	{KeyRightbrace, "KEY_RIGHTBRACE", "KeyRightbrace"},
+	{KeyEnter, "KEY_ENTER", "KeyEnter"},
+	{KeyLeftctrl, "KEY_LEFTCTRL", "KeyLeftctrl"},
+	{KeyA, "KEY_A", "KeyA"},
+	{KeyS, "KEY_S", "KeyS"},
+	{KeyD, "KEY_D", "KeyD"},
+	{KeyF, "KEY_F", "KeyF"},
+	{KeyG, "KEY_G", "KeyG"},
+	{KeyH, "KEY_H", "KeyH"},
+	{KeyJ, "KEY_J", "KeyJ"},
+	{KeyK, "KEY_K", "KeyK"},
+	{KeyL, "KEY_L", "KeyL"},
+	{KeySemicolon, "KEY_SEMICOLON", "KeySemicolon"},
+	{KeyApostrophe, "KEY_APOSTROPHE", "KeyApostrophe"},
+	{KeyGrave, "KEY_GRAVE", "KeyGrave"},
+	{KeyLeftshift, "KEY_LEFTSHIFT", "KeyLeftshift"},
+	{KeyBackslash, "KEY_BACKSLASH", "KeyBackslash"},
+	{KeyZ, "KEY_Z", "KeyZ"},
+	{KeyX, "KEY_X", "KeyX"},
+	{KeyC, "KEY_C", "KeyC"},
+	{KeyV, "KEY_V", "KeyV"},
+	{KeyB, "KEY_B", "KeyB"},
+	{KeyN, "KEY_N", "KeyN"},
+	{KeyM, "KEY_M", "KeyM"},
+	{KeyComma, "KEY_COMMA", "KeyComma"},
+	{KeyDot, "KEY_DOT", "KeyDot"},
+	{KeySlash, "KEY_SLASH", "KeySlash"},
+	{KeyRightshift, "KEY_RIGHTSHIFT", "KeyRightshift"},
+	{KeyKpasterisk, "KEY_KPASTERISK", "KeyKpasterisk"},
+	{KeyLeftalt, "KEY_LEFTALT", "KeyLeftalt"},
+	{KeySpace, "KEY_SPACE", "KeySpace"},
+	{KeyCapslock, "KEY_CAPSLOCK", "KeyCapslock"},
+	{KeyF1, "KEY_F1", "KeyF1"},
+	{KeyF2, "KEY_F2", "KeyF2"},
+	{KeyF3, "KEY_F3", "KeyF3"},
+	{KeyF4, "KEY_F4", "KeyF4"},
+	{KeyF5, "KEY_F5", "KeyF5"},
+	{KeyF6, "KEY_F6", "KeyF6"},
+	{KeyF7, "KEY_F7", "KeyF7"},
+	{KeyF8, "KEY_F8", "KeyF8"},
+	{KeyF9, "KEY_F9", "KeyF9"},
+	{KeyF10, "KEY_F10", "KeyF10"},
+	{KeyNumlock, "KEY_NUMLOCK", "KeyNumlock"},
+	{KeyScrolllock, "KEY_SCROLLLOCK", "KeyScrolllock"},
+	{KeyKp7, "KEY_KP7", "KeyKp7"},
+	{KeyKp8, "KEY_KP8", "KeyKp8"},
+	{KeyKp9, "KEY_KP9", "KeyKp9"},
+	{KeyKpminus, "KEY_KPMINUS", "KeyKpminus"},
+	{KeyKp4, "KEY_KP4", "KeyKp4"},
+	{KeyKp5, "KEY_KP5", "KeyKp5"},
+	{KeyKp6, "KEY_KP6", "KeyKp6"},
+	{KeyKpplus, "KEY_KPPLUS", "KeyKpplus"},
+	{KeyKp1, "KEY_KP1", "KeyKp1"},
+	{KeyKp2, "KEY_KP2", "KeyKp2"},
+	{KeyKp3, "KEY_KP3", "KeyKp3"},
+	{KeyKp0, "KEY_KP0", "KeyKp0"},
+	{KeyKpdot, "KEY_KPDOT", "KeyKpdot"},
+	{KeyZenkakuhankaku, "KEY_ZENKAKUHANKAKU", "KeyZenkakuhankaku"},
+	{Key102Nd, "KEY_102ND", "Key102Nd"},
+	{KeyF11, "KEY_F11", "KeyF11"},
+	{KeyF12, "KEY_F12", "KeyF12"},
+	{KeyRo, "KEY_RO", "KeyRo"},
+	{KeyKatakana, "KEY_KATAKANA", "KeyKatakana"},
+	{KeyHiragana, "KEY_HIRAGANA", "KeyHiragana"},
+	{KeyHenkan, "KEY_HENKAN", "KeyHenkan"},
+	{KeyKatakanahiragana, "KEY_KATAKANAHIRAGANA", "KeyKatakanahiragana"},
+	{KeyMuhenkan, "KEY_MUHENKAN", "KeyMuhenkan"},
+	{KeyKpjpcomma, "KEY_KPJPCOMMA", "KeyKpjpcomma"},
+	{KeyKpenter, "KEY_KPENTER", "KeyKpenter"},
+	{KeyRightctrl, "KEY_RIGHTCTRL", "KeyRightctrl"},
+	{KeyKpslash, "KEY_KPSLASH", "KeyKpslash"},
+	{KeySysrq, "KEY_SYSRQ", "KeySysrq"},
+	{KeyRightalt, "KEY_RIGHTALT", "KeyRightalt"},
+	{KeyLinefeed, "KEY_LINEFEED", "KeyLinefeed"},
+	{KeyHome, "KEY_HOME", "KeyHome"},
+	{KeyUp, "KEY_UP", "KeyUp"},
+	{KeyPageup, "KEY_PAGEUP", "KeyPageup"},
+	{KeyLeft, "KEY_LEFT", "KeyLeft"},
+	{KeyRight, "KEY_RIGHT", "KeyRight"},
+	{KeyEnd, "KEY_END", "KeyEnd"},
+	{KeyDown, "KEY_DOWN", "KeyDown"},
+	{KeyPagedown, "KEY_PAGEDOWN", "KeyPagedown"},
+	{KeyInsert, "KEY_INSERT", "KeyInsert"},
+	{KeyDelete, "KEY_DELETE", "KeyDelete"},
+	{KeyMacro, "KEY_MACRO", "KeyMacro"},
+	{KeyMute, "KEY_MUTE", "KeyMute"},
+	{KeyVolumedown, "KEY_VOLUMEDOWN", "KeyVolumedown"},
+	{KeyVolumeup, "KEY_VOLUMEUP", "KeyVolumeup"},
+	{KeyPower, "KEY_POWER", "KeyPower"},
+	{KeyKpequal, "KEY_KPEQUAL", "KeyKpequal"},
+	{KeyKpplusminus, "KEY_KPPLUSMINUS", "KeyKpplusminus"},
+	{KeyPause, "KEY_PAUSE", "KeyPause"},
+	{KeyScale, "KEY_SCALE", "KeyScale"},
+	{KeyKpcomma, "KEY_KPCOMMA", "KeyKpcomma"},
+	{KeyHangeul, "KEY_HANGEUL", "KeyHangeul"},
+	{KeyHanja, "KEY_HANJA", "KeyHanja"},
+	{KeyYen, "KEY_YEN", "KeyYen"},
+	{KeyLeftmeta, "KEY_LEFTMETA", "KeyLeftmeta"},
+	{KeyRightmeta, "KEY_RIGHTMETA", "KeyRightmeta"},
+	{KeyCompose, "KEY_COMPOSE", "KeyCompose"},
+	{KeyStop, "KEY_STOP", "KeyStop"},
+	{KeyAgain, "KEY_AGAIN", "KeyAgain"},
+	{KeyProps, "KEY_PROPS", "KeyProps"},
+	{KeyUndo, "KEY_UNDO", "KeyUndo"},
+	{KeyFront, "KEY_FRONT", "KeyFront"},
+	{KeyCopy, "KEY_COPY", "KeyCopy"},
+	{KeyOpen, "KEY_OPEN", "KeyOpen"},
+	{KeyPaste, "KEY_PASTE", "KeyPaste"},
+	{KeyFind, "KEY_FIND", "KeyFind"},
+	{KeyCut, "KEY_CUT", "KeyCut"},
+	{KeyHelp, "KEY_HELP", "KeyHelp"},
+	{KeyMenu, "KEY_MENU", "KeyMenu"},
+	{KeyCalc, "KEY_CALC", "KeyCalc"},
+	{KeySetup, "KEY_SETUP", "KeySetup"},
+	{KeySleep, "KEY_SLEEP", "KeySleep"},
+	{KeyWakeup, "KEY_WAKEUP", "KeyWakeup"},
+	{KeyFile, "KEY_FILE", "KeyFile"},
+	{KeySendfile, "KEY_SENDFILE", "KeySendfile"},
+	{KeyDeletefile, "KEY_DELETEFILE", "KeyDeletefile"},
+	{KeyXfer, "KEY_XFER", "KeyXfer"},
+	{KeyProg1, "KEY_PROG1", "KeyProg1"},
+	{KeyProg2, "KEY_PROG2", "KeyProg2"},
+	{KeyWww, "KEY_WWW", "KeyWww"},
+	{KeyMsdos, "KEY_MSDOS", "KeyMsdos"},
+	{KeyCoffee, "KEY_COFFEE", "KeyCoffee"},
+	{KeyDirection, "KEY_DIRECTION", "KeyDirection"},
+	{KeyCyclewindows, "KEY_CYCLEWINDOWS", "KeyCyclewindows"},
+	{KeyMail, "KEY_MAIL", "KeyMail"},
+	{KeyBookmarks, "KEY_BOOKMARKS", "KeyBookmarks"},
+	{KeyComputer, "KEY_COMPUTER", "KeyComputer"},
+	{KeyBack, "KEY_BACK", "KeyBack"},
+	{KeyForward, "KEY_FORWARD", "KeyForward"},
+	{KeyClosecd, "KEY_CLOSECD", "KeyClosecd"},
+	{KeyEjectcd, "KEY_EJECTCD", "KeyEjectcd"},
+	{KeyEjectclosecd, "KEY_EJECTCLOSECD", "KeyEjectclosecd"},
+	{KeyNextsong, "KEY_NEXTSONG", "KeyNextsong"},
+	{KeyPlaypause, "KEY_PLAYPAUSE", "KeyPlaypause"},
+	{KeyPrevioussong, "KEY_PREVIOUSSONG", "KeyPrevioussong"},
+	{KeyStopcd, "KEY_STOPCD", "KeyStopcd"},
+	{KeyRecord, "KEY_RECORD", "KeyRecord"},
+	{KeyRewind, "KEY_REWIND", "KeyRewind"},
+	{KeyPhone, "KEY_PHONE", "KeyPhone"},
+	{KeyIso, "KEY_ISO", "KeyIso"},
+	{KeyConfig, "KEY_CONFIG", "KeyConfig"},
+	{KeyHomepage, "KEY_HOMEPAGE", "KeyHomepage"},
+	{KeyRefresh, "KEY_REFRESH", "KeyRefresh"},
+	{KeyExit, "KEY_EXIT", "KeyExit"},
+	{KeyMove, "KEY_MOVE", "KeyMove"},
+	{KeyEdit, "KEY_EDIT", "KeyEdit"},
+	{KeyScrollup, "KEY_SCROLLUP", "KeyScrollup"},
+	{KeyScrolldown, "KEY_SCROLLDOWN", "KeyScrolldown"},
+	{KeyKpleftparen, "KEY_KPLEFTPAREN", "KeyKpleftparen"},
+	{KeyKprightparen, "KEY_KPRIGHTPAREN", "KeyKprightparen"},
+	{KeyNew, "KEY_NEW", "KeyNew"},
+	{KeyRedo, "KEY_REDO", "KeyRedo"},
+	{KeyF13, "KEY_F13", "KeyF13"},
+	{KeyF14, "KEY_F14", "KeyF14"},
+	{KeyF15, "KEY_F15", "KeyF15"},
+	{KeyF16, "KEY_F16", "KeyF16"},
+	{KeyF17, "KEY_F17", "KeyF17"},
+	{KeyF18, "KEY_F18", "KeyF18"},
+	{KeyF19, "KEY_F19", "KeyF19"},
+	{KeyF20, "KEY_F20", "KeyF20"},
+	{KeyF21, "KEY_F21", "KeyF21"},
+	{KeyF22, "KEY_F22", "KeyF22"},
+	{KeyF23, "KEY_F23", "KeyF23"},
+	{KeyF24, "KEY_F24", "KeyF24"},
+	{KeyPlaycd, "KEY_PLAYCD", "KeyPlaycd"},
+	{KeyPausecd, "KEY_PAUSECD", "KeyPausecd"},
+	{KeyProg3, "KEY_PROG3", "KeyProg3"},
+	{KeyProg4, "KEY_PROG4", "KeyProg4"},
+	{KeyDashboard, "KEY_DASHBOARD", "KeyDashboard"},
+	{KeySuspend, "KEY_SUSPEND", "KeySuspend"},
+	{KeyClose, "KEY_CLOSE", "KeyClose"},
+	{KeyPlay, "KEY_PLAY", "KeyPlay"},
+	{KeyFastforward, "KEY_FASTFORWARD", "KeyFastforward"},
+	{KeyBassboost, "KEY_BASSBOOST", "KeyBassboost"},
+	{KeyPrint, "KEY_PRINT", "KeyPrint"},
+	{KeyHp, "KEY_HP", "KeyHp"},
+	{KeyCamera, "KEY_CAMERA", "KeyCamera"},
+	{KeySound, "KEY_SOUND", "KeySound"},
+	{KeyQuestion, "KEY_QUESTION", "KeyQuestion"},
+	{KeyEmail, "KEY_EMAIL", "KeyEmail"},
+	{KeyChat, "KEY_CHAT", "KeyChat"},
+	{KeySearch, "KEY_SEARCH", "KeySearch"},
+	{KeyConnect, "KEY_CONNECT", "KeyConnect"},
+	{KeyFinance, "KEY_FINANCE", "KeyFinance"},
+	{KeySport, "KEY_SPORT", "KeySport"},
+	{KeyShop, "KEY_SHOP", "KeyShop"},
+	{KeyAlterase, "KEY_ALTERASE", "KeyAlterase"},
+	{KeyCancel, "KEY_CANCEL", "KeyCancel"},
+	{KeyBrightnessdown, "KEY_BRIGHTNESSDOWN", "KeyBrightnessdown"},
+	{KeyBrightnessup, "KEY_BRIGHTNESSUP", "KeyBrightnessup"},
+	{KeyMedia, "KEY_MEDIA", "KeyMedia"},
+	{KeySwitchvideomode, "KEY_SWITCHVIDEOMODE", "KeySwitchvideomode"},
+	{KeyKbdillumtoggle, "KEY_KBDILLUMTOGGLE", "KeyKbdillumtoggle"},
+	{KeyKbdillumdown, "KEY_KBDILLUMDOWN", "KeyKbdillumdown"},
+	{KeyKbdillumup, "KEY_KBDILLUMUP", "KeyKbdillumup"},
+	{KeySend, "KEY_SEND", "KeySend"},
+	{KeyReply, "KEY_REPLY", "KeyReply"},
+	{KeyForwardmail, "KEY_FORWARDMAIL", "KeyForwardmail"},
+	{KeySave, "KEY_SAVE", "KeySave"},
+	{KeyDocuments, "KEY_DOCUMENTS", "KeyDocuments"},
+	{KeyBattery, "KEY_BATTERY", "KeyBattery"},
+	{KeyBluetooth, "KEY_BLUETOOTH", "KeyBluetooth"},
+	{KeyWlan, "KEY_WLAN", "KeyWlan"},
+	{KeyUwb, "KEY_UWB", "KeyUwb"},
+	{KeyUnknown, "KEY_UNKNOWN", "KeyUnknown"},
+	{KeyVideoNext, "KEY_VIDEO_NEXT", "KeyVideoNext"},
+	{KeyVideoPrev, "KEY_VIDEO_PREV", "KeyVideoPrev"},
+	{KeyBrightnessCycle, "KEY_BRIGHTNESS_CYCLE", "KeyBrightnessCycle"},
+	{KeyBrightnessZero, "KEY_BRIGHTNESS_ZERO", "KeyBrightnessZero"},
+	{KeyDisplayOff, "KEY_DISPLAY_OFF", "KeyDisplayOff"},
+	{KeyWimax, "KEY_WIMAX", "KeyWimax"},
+	{KeyRfkill, "KEY_RFKILL", "KeyRfkill"},
+	{KeyMicmute, "KEY_MICMUTE", "KeyMicmute"},
+	{ButtonLeft, "BTN_LEFT", "ButtonLeft"},
+	{ButtonRight, "BTN_RIGHT", "ButtonRight"},
+	{ButtonMiddle, "BTN_MIDDLE", "ButtonMiddle"},
+	{ButtonSide, "BTN_SIDE", "ButtonSide"},
+	{ButtonExtra, "BTN_EXTRA", "ButtonExtra"},
+	{ButtonForward, "BTN_FORWARD", "ButtonForward"},
+	{ButtonBack, "BTN_BACK", "ButtonBack"},
+	{ButtonTask, "BTN_TASK", "ButtonTask"},
+	{ButtonSouth, "BTN_SOUTH", "ButtonSouth"},
+	{ButtonGamepad, "BTN_GAMEPAD", "ButtonGamepad"},
+	{ButtonEast, "BTN_EAST", "ButtonEast"},
+	{ButtonNorth, "BTN_NORTH", "ButtonNorth"},
+	{ButtonWest, "BTN_WEST", "ButtonWest"},
+	{ButtonBumperLeft, "BTN_TL", "ButtonBumperLeft"},
+	{ButtonBumperRight, "BTN_TR", "ButtonBumperRight"},
+	{ButtonTriggerLeft, "BTN_TL2", "ButtonTriggerLeft"},
+	{ButtonTriggerRight, "BTN_TR2", "ButtonTriggerRight"},
+	{ButtonThumbLeft, "BTN_THUMBL", "ButtonThumbLeft"},
+	{ButtonThumbRight, "BTN_THUMBR", "ButtonThumbRight"},
+	{ButtonSelect, "BTN_SELECT", "ButtonSelect"},
+	{ButtonStart, "BTN_START", "ButtonStart"},
+	{ButtonDpadUp, "BTN_DPAD_UP", "ButtonDpadUp"},
+	{ButtonDpadDown, "BTN_DPAD_DOWN", "ButtonDpadDown"},
+	{ButtonDpadLeft, "BTN_DPAD_LEFT", "ButtonDpadLeft"},
+	{ButtonDpadRight, "BTN_DPAD_RIGHT", "ButtonDpadRight"},
+	{ButtonMode, "BTN_MODE", "ButtonMode"},
+	{ButtonToolPen, "BTN_TOOL_PEN", "ButtonToolPen"},
+	{ButtonToolRubber, "BTN_TOOL_RUBBER", "ButtonToolRubber"},
+	{ButtonToolFinger, "BTN_TOOL_FINGER", "ButtonToolFinger"},
+	{ButtonTouch, "BTN_TOUCH", "ButtonTouch"},
+	{ButtonStylus, "BTN_STYLUS", "ButtonStylus"},
+	{ButtonStylus2, "BTN_STYLUS2", "ButtonStylus2"},
+	{ButtonToolDoubletap, "BTN_TOOL_DOUBLETAP", "ButtonToolDoubletap"},
+	{ButtonToolTripletap, "BTN_TOOL_TRIPLETAP", "ButtonToolTripletap"},
+	{ButtonToolQuadtap, "BTN_TOOL_QUADTAP", "ButtonToolQuadtap"},
+}
+
+// keyAliases are names of key codes that the kernel defines in addition to the names in keyNameTable. Some of them
+// replaced the names used in keycodes.go in newer kernel versions.
+var keyAliases = map[string]int{
+	"KEY_HANGUEL":          KeyHangeul,
+	"KEY_SCREENLOCK":       KeyCoffee,
+	"KEY_ROTATE_DISPLAY":   KeyDirection,
+	"KEY_ALL_APPLICATIONS": KeyDashboard,
+	"KEY_BRIGHTNESS_AUTO":  KeyBrightnessZero,
+	"KEY_WWAN":             KeyWimax,
+	"BTN_MOUSE":            ButtonLeft,
+	"BTN_A":                ButtonSouth,
+	"BTN_B":                ButtonEast,
+	"BTN_X":                ButtonNorth,
+	"BTN_Y":                ButtonWest,
+}
+
+var (
+	keyNamesByCode = make(map[int]string)
+	keyCodesByName = make(map[string]int)
+)
+
+func init() {
+	for _, k := range keyNameTable {
+		if _, ok := keyNamesByCode[k.code]; !ok {
+			keyNamesByCode[k.code] = k.name
+		}
+		keyCodesByName[k.name] = k.code
+		keyCodesByName[k.constant] = k.code
+	}
+	for name, code := range keyAliases {
+		keyCodesByName[name] = code
+	}
+}
+
+// KeyName returns the name of a key or button code as used by the kernel, for example "KEY_A" for KeyA or "BTN_SOUTH"
+// for ButtonSouth. The result is an empty string if the code is unknown.
+func KeyName(code int) string {
+	return keyNamesByCode[code]
+}
+
+// KeyByName returns the key or button code of the given name. Both the name used by the kernel ("KEY_VOLUMEUP") and
+// the name of the constant in keycodes.go ("KeyVolumeup") are accepted. Kernel names are case insensitive. The result
+// is false if the name is unknown.
+func KeyByName(name string) (int, bool) {
+	if code, ok := keyCodesByName[name]; ok {
+		return code, true
+	}
+	upper := strings.ToUpper(name)
+	if !strings.HasPrefix(upper, "KEY_") && !strings.HasPrefix(upper, "BTN_") {
+		return 0, false
+	}
+	code, ok := keyCodesByName[upper]
+	return code, ok
+}
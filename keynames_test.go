@@ -0,0 +1,122 @@
+package uinput
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+)
+
+// keyConstants returns the values of all exported constants in keycodes.go.
+func keyConstants(t *testing.T) map[string]int {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "keycodes.go", nil, 0)
+	if err != nil {
+		t.Fatalf("Failed to parse keycodes.go: %v", err)
+	}
+	info := &types.Info{Defs: make(map[*ast.Ident]types.Object)}
+	_, err = (&types.Config{}).Check("uinput", fset, []*ast.File{file}, info)
+	if err != nil {
+		t.Fatalf("Failed to check keycodes.go: %v", err)
+	}
+
+	constants := make(map[string]int)
+	for ident, obj := range info.Defs {
+		if c, ok := obj.(*types.Const); ok && ident.IsExported() {
+			value, _ := constant.Int64Val(c.Val())
+			constants[ident.Name] = int(value)
+		}
+	}
+	return constants
+}
+
+func TestKeyNamesCoverAllKeyCodes(t *testing.T) {
+	constants := keyConstants(t)
+	if len(constants) < 270 {
+		t.Fatalf("Expected all key codes to be found in keycodes.go, but got only %d", len(constants))
+	}
+
+	for constantName, code := range constants {
+		if actual, ok := KeyByName(constantName); !ok || actual != code {
+			t.Fatalf("Expected %s to map to %#x, but got %#x (%v)", constantName, code, actual, ok)
+		}
+		name := KeyName(code)
+		if !strings.HasPrefix(name, "KEY_") && !strings.HasPrefix(name, "BTN_") {
+			t.Fatalf("Expected %s (%#x) to have a name, but got %q", constantName, code, name)
+		}
+		if actual, ok := KeyByName(name); !ok || actual != code {
+			t.Fatalf("Expected %s to map back to %#x, but got %#x (%v)", name, code, actual, ok)
+		}
+	}
+}
+
+func TestKeyNamesAreConsistent(t *testing.T) {
+	for _, k := range keyNameTable {
+		if actual, ok := KeyByName(k.name); !ok || actual != k.code {
+			t.Fatalf("Expected %s to map to %#x, but got %#x (%v)", k.name, k.code, actual, ok)
+		}
+		if KeyName(k.code) == "" {
+			t.Fatalf("Expected %#x to have a name", k.code)
+		}
+	}
+	for alias, code := range keyAliases {
+		if KeyName(code) == alias {
+			t.Fatalf("Expected the alias %s not to be the primary name of %#x", alias, code)
+		}
+	}
+}
+
+func TestKeyName(t *testing.T) {
+	tests := []struct {
+		code     int
+		expected string
+	}{
+		{KeyA, "KEY_A"},
+		{KeyVolumeup, "KEY_VOLUMEUP"},
+		{Key102Nd, "KEY_102ND"},
+		{KeyVideoNext, "KEY_VIDEO_NEXT"},
+		{ButtonLeft, "BTN_LEFT"},
+		{ButtonSouth, "BTN_SOUTH"},
+		{ButtonBumperLeft, "BTN_TL"},
+		{ButtonDpadUp, "BTN_DPAD_UP"},
+		{84, ""},
+		{-1, ""},
+	}
+
+	for _, test := range tests {
+		if actual := KeyName(test.code); actual != test.expected {
+			t.Fatalf("Expected %#x to be named %q, but got %q", test.code, test.expected, actual)
+		}
+	}
+}
+
+func TestKeyByName(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected int
+		ok       bool
+	}{
+		{"KEY_A", KeyA, true},
+		{"KeyA", KeyA, true},
+		{"key_volumeup", KeyVolumeup, true},
+		{"BTN_GAMEPAD", ButtonSouth, true},
+		{"BTN_A", ButtonSouth, true},
+		{"ButtonGamepad", ButtonSouth, true},
+		{"KEY_SCREENLOCK", KeyCoffee, true},
+		{"KEY_WWAN", KeyWimax, true},
+		{"keya", 0, false},
+		{"A", 0, false},
+		{"KEY_NOSUCHKEY", 0, false},
+		{"", 0, false},
+	}
+
+	for _, test := range tests {
+		actual, ok := KeyByName(test.name)
+		if actual != test.expected || ok != test.ok {
+			t.Fatalf("Expected %q to map to %#x (%v), but got %#x (%v)", test.name, test.expected, test.ok, actual, ok)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package uinput
+
+// Modifier is a modifier key that needs to be held down in order to type a character.
+type Modifier int
+
+const (
+	// ModShift is the left shift key.
+	ModShift Modifier = 1 << iota
+	// ModAltGr is the right alt key (AltGr), which selects the third level of a key on most european layouts.
+	ModAltGr
+	// ModCtrl is the left control key.
+	ModCtrl
+)
+
+// Keystroke is a single key press along with the modifiers that are held down while the key is pressed.
+type Keystroke struct {
+	Key       int
+	Modifiers Modifier
+}
+
+// A Layout maps characters to the keystrokes that produce them. Since the kernel only deals with key codes, the layout
+// needs to match the keyboard layout the system uses for the virtual keyboard (see WithLayout).
+type Layout interface {
+	// Name returns the name of the layout, for example "us".
+	Name() string
+
+	// Keystrokes returns the keystrokes that produce the given character. Characters that are composed using a dead
+	// key require more than one keystroke. The result is false if the character can not be typed using the layout.
+	Keystrokes(r rune) ([]Keystroke, bool)
+}
+
+// layoutKey lists the characters a key produces on each level: unshifted, shifted and with AltGr. A zero rune means
+// that the level does not produce a character, combining characters (see deadKeys) denote dead keys.
+type layoutKey struct {
+	key    int
+	levels string
+}
+
+// accent is the accent of a dead key, along with the characters that result from pressing the dead key followed by
+// a base character.
+type accent struct {
+	// spacing is the accent on its own, which is typed by pressing the dead key twice.
+	spacing      rune
+	compositions map[rune]rune
+}
+
+// deadKeys maps the combining characters that denote dead keys in a layoutKey to their accents: grave, acute,
+// circumflex, tilde and diaeresis.
+var deadKeys = map[rune]accent{
+	'\u0300': {spacing: '`', compositions: compose("aeiouAEIOU", "àèìòùÀÈÌÒÙ")},
+	'\u0301': {spacing: '´', compositions: compose("aeiouyAEIOUY", "áéíóúýÁÉÍÓÚÝ")},
+	'\u0302': {spacing: '^', compositions: compose("aeiouAEIOU", "âêîôûÂÊÎÔÛ")},
+	'\u0303': {spacing: '~', compositions: compose("anoANO", "ãñõÃÑÕ")},
+	'\u0308': {spacing: '¨', compositions: compose("aeiouyAEIOU", "äëïöüÿÄËÏÖÜ")},
+}
+
+func compose(bases string, composed string) map[rune]rune {
+	compositions := make(map[rune]rune)
+	results := []rune(composed)
+	for i, base := range []rune(bases) {
+		compositions[base] = results[i]
+	}
+	return compositions
+}
+
+// keymap is a Layout that is defined by the characters of each key.
+type keymap struct {
+	name    string
+	strokes map[rune][]Keystroke
+}
+
+// newKeymap creates a layout from the given keys. If a character is produced by more than one key, the keystroke
+// that requires the least modifiers is used. Characters that are composed using dead keys are only used if they can
+// not be typed directly.
+func newKeymap(name string, keys []layoutKey) *keymap {
+	m := &keymap{name: name, strokes: map[rune][]Keystroke{
+		' ':  {{Key: KeySpace}},
+		'\t': {{Key: KeyTab}},
+		'\n': {{Key: KeyEnter}},
+	}}
+
+	type deadKey struct {
+		stroke Keystroke
+		accent accent
+	}
+	var dead []deadKey
+	for level, modifiers := range []Modifier{0, ModShift, ModAltGr} {
+		for _, k := range keys {
+			levels := []rune(k.levels)
+			if level >= len(levels) || levels[level] == 0 {
+				continue
+			}
+			stroke := Keystroke{Key: k.key, Modifiers: modifiers}
+			if a, ok := deadKeys[levels[level]]; ok {
+				dead = append(dead, deadKey{stroke: stroke, accent: a})
+				continue
+			}
+			m.add(levels[level], stroke)
+		}
+	}
+
+	for _, d := range dead {
+		m.add(d.accent.spacing, d.stroke, d.stroke)
+		for base, composed := range d.accent.compositions {
+			if strokes, ok := m.strokes[base]; ok && len(strokes) == 1 {
+				m.add(composed, d.stroke, strokes[0])
+			}
+		}
+	}
+	return m
+}
+
+// add registers the keystrokes of a character, unless the character can already be typed.
+func (m *keymap) add(r rune, strokes ...Keystroke) {
+	if _, ok := m.strokes[r]; !ok {
+		m.strokes[r] = strokes
+	}
+}
+
+// Name returns the name of the layout.
+func (m *keymap) Name() string {
+	return m.name
+}
+
+// Keystrokes returns the keystrokes that produce the given character.
+func (m *keymap) Keystrokes(r rune) ([]Keystroke, bool) {
+	strokes, ok := m.strokes[r]
+	return strokes, ok
+}
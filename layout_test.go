@@ -0,0 +1,90 @@
+package uinput
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLayoutKeystrokes(t *testing.T) {
+	shift := func(key int) Keystroke { return Keystroke{Key: key, Modifiers: ModShift} }
+	altGr := func(key int) Keystroke { return Keystroke{Key: key, Modifiers: ModAltGr} }
+	key := func(key int) Keystroke { return Keystroke{Key: key} }
+
+	tests := []struct {
+		layout   Layout
+		char     rune
+		expected []Keystroke
+	}{
+		{LayoutUS, 'a', []Keystroke{key(KeyA)}},
+		{LayoutUS, 'A', []Keystroke{shift(KeyA)}},
+		{LayoutUS, '@', []Keystroke{shift(Key2)}},
+		{LayoutUS, '"', []Keystroke{shift(KeyApostrophe)}},
+		{LayoutUS, '\n', []Keystroke{key(KeyEnter)}},
+
+		{LayoutUK, '"', []Keystroke{shift(Key2)}},
+		{LayoutUK, '@', []Keystroke{shift(KeyApostrophe)}},
+		{LayoutUK, '£', []Keystroke{shift(Key3)}},
+		{LayoutUK, '€', []Keystroke{altGr(Key4)}},
+		{LayoutUK, '#', []Keystroke{key(KeyBackslash)}},
+		{LayoutUK, '\\', []Keystroke{key(Key102Nd)}},
+
+		{LayoutGerman, 'z', []Keystroke{key(KeyY)}},
+		{LayoutGerman, 'Y', []Keystroke{shift(KeyZ)}},
+		{LayoutGerman, 'ß', []Keystroke{key(KeyMinus)}},
+		{LayoutGerman, 'Ü', []Keystroke{shift(KeyLeftbrace)}},
+		{LayoutGerman, '@', []Keystroke{altGr(KeyQ)}},
+		{LayoutGerman, '{', []Keystroke{altGr(Key7)}},
+		{LayoutGerman, '^', []Keystroke{key(KeyGrave), key(KeyGrave)}},
+		{LayoutGerman, 'é', []Keystroke{key(KeyEqual), key(KeyE)}},
+		{LayoutGerman, 'À', []Keystroke{shift(KeyEqual), shift(KeyA)}},
+		{LayoutGerman, 'ô', []Keystroke{key(KeyGrave), key(KeyO)}},
+
+		{LayoutFrench, 'a', []Keystroke{key(KeyQ)}},
+		{LayoutFrench, 'q', []Keystroke{key(KeyA)}},
+		{LayoutFrench, 'm', []Keystroke{key(KeySemicolon)}},
+		{LayoutFrench, '1', []Keystroke{shift(Key1)}},
+		{LayoutFrench, 'é', []Keystroke{key(Key2)}},
+		{LayoutFrench, '@', []Keystroke{altGr(Key0)}},
+		{LayoutFrench, '^', []Keystroke{altGr(Key9)}},
+		{LayoutFrench, 'ê', []Keystroke{key(KeyLeftbrace), key(KeyE)}},
+		{LayoutFrench, 'Ï', []Keystroke{shift(KeyLeftbrace), shift(KeyI)}},
+		{LayoutFrench, '¨', []Keystroke{shift(KeyLeftbrace), shift(KeyLeftbrace)}},
+
+		{LayoutNordic, 'å', []Keystroke{key(KeyLeftbrace)}},
+		{LayoutNordic, 'Ö', []Keystroke{shift(KeySemicolon)}},
+		{LayoutNordic, '@', []Keystroke{altGr(Key2)}},
+		{LayoutNordic, '$', []Keystroke{altGr(Key4)}},
+		{LayoutNordic, '~', []Keystroke{altGr(KeyRightbrace), altGr(KeyRightbrace)}},
+		{LayoutNordic, 'ñ', []Keystroke{altGr(KeyRightbrace), key(KeyN)}},
+		{LayoutNordic, 'ü', []Keystroke{key(KeyRightbrace), key(KeyU)}},
+		{LayoutNordic, '´', []Keystroke{key(KeyEqual), key(KeyEqual)}},
+	}
+
+	for _, test := range tests {
+		actual, ok := test.layout.Keystrokes(test.char)
+		if !ok {
+			t.Fatalf("Expected %q to be supported by layout %q", test.char, test.layout.Name())
+		}
+		if !reflect.DeepEqual(test.expected, actual) {
+			t.Fatalf("Layout %q, character %q\nExpected: %+v\nActual: %+v", test.layout.Name(), test.char, test.expected, actual)
+		}
+	}
+}
+
+func TestLayoutsSupportPrintableASCII(t *testing.T) {
+	for _, layout := range []Layout{LayoutUS, LayoutUK, LayoutGerman, LayoutFrench, LayoutNordic} {
+		for r := rune(' '); r <= '~'; r++ {
+			if _, ok := layout.Keystrokes(r); !ok {
+				t.Fatalf("Expected %q to be supported by layout %q", r, layout.Name())
+			}
+		}
+	}
+}
+
+func TestLayoutRejectsUnsupportedCharacters(t *testing.T) {
+	for _, r := range []rune{'ß', 'é', '€', '☃'} {
+		if strokes, ok := LayoutUS.Keystrokes(r); ok {
+			t.Fatalf("Expected %q not to be supported by the US layout, but got %+v", r, strokes)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package uinput
+
+// The built-in layouts follow the default variants of the corresponding xkb layouts. Each row lists the characters of
+// its keys from left to right (see layoutKey); only the commonly used characters of the AltGr level are included.
+var (
+	// LayoutUS is the US English layout (xkb: us).
+	LayoutUS Layout = newKeymap("us", layoutRows(
+		[]string{"`~", "1!", "2@", "3#", "4$", "5%", "6^", "7&", "8*", "9(", "0)", "-_", "=+"},
+		[]string{"qQ", "wW", "eE", "rR", "tT", "yY", "uU", "iI", "oO", "pP", "[{", "]}"},
+		[]string{"aA", "sS", "dD", "fF", "gG", "hH", "jJ", "kK", "lL", ";:", "'\"", "\\|"},
+		[]string{"", "zZ", "xX", "cC", "vV", "bB", "nN", "mM", ",<", ".>", "/?"},
+	))
+
+	// LayoutUK is the British English layout (xkb: gb).
+	LayoutUK Layout = newKeymap("gb", layoutRows(
+		[]string{"`¬", "1!", "2\"", "3£", "4$€", "5%", "6^", "7&", "8*", "9(", "0)", "-_", "=+"},
+		[]string{"qQ", "wW", "eE", "rR", "tT", "yY", "uU", "iI", "oO", "pP", "[{", "]}"},
+		[]string{"aA", "sS", "dD", "fF", "gG", "hH", "jJ", "kK", "lL", ";:", "'@", "#~"},
+		[]string{"\\|", "zZ", "xX", "cC", "vV", "bB", "nN", "mM", ",<", ".>", "/?"},
+	))
+
+	// LayoutGerman is the German QWERTZ layout with dead keys (xkb: de).
+	LayoutGerman Layout = newKeymap("de", layoutRows(
+		[]string{"\u0302°", "1!", "2\"²", "3§³", "4$", "5%", "6&", "7/{", "8([", "9)]", "0=}", "ß?\\", "\u0301\u0300"},
+		[]string{"qQ@", "wW", "eE€", "rR", "tT", "zZ", "uU", "iI", "oO", "pP", "üÜ", "+*~"},
+		[]string{"aA", "sS", "dD", "fF", "gG", "hH", "jJ", "kK", "lL", "öÖ", "äÄ", "#'"},
+		[]string{"<>|", "yY", "xX", "cC", "vV", "bB", "nN", "mMµ", ",;", ".:", "-_"},
+	))
+
+	// LayoutFrench is the French AZERTY layout (xkb: fr).
+	LayoutFrench Layout = newKeymap("fr", layoutRows(
+		[]string{"²~", "&1", "é2~", "\"3#", "'4{", "(5[", "-6|", "è7`", "_8\\", "ç9^", "à0@", ")°]", "=+}"},
+		[]string{"aA", "zZ", "eE€", "rR", "tT", "yY", "uU", "iI", "oO", "pP", "\u0302\u0308", "$£"},
+		[]string{"qQ", "sS", "dD", "fF", "gG", "hH", "jJ", "kK", "lL", "mM", "ù%", "*µ"},
+		[]string{"<>", "wW", "xX", "cC", "vV", "bB", "nN", ",?", ";.", ":/", "!§"},
+	))
+
+	// LayoutNordic is the Swedish layout (xkb: se), which also covers the Finnish layout.
+	LayoutNordic Layout = newKeymap("se", layoutRows(
+		[]string{"§½", "1!", "2\"@", "3#£", "4¤$", "5%€", "6&", "7/{", "8([", "9)]", "0=}", "+?\\", "\u0301\u0300"},
+		[]string{"qQ@", "wW", "eE€", "rR", "tT", "yY", "uU", "iI", "oO", "pP", "åÅ", "\u0308\u0302\u0303"},
+		[]string{"aA", "sS", "dD", "fF", "gG", "hH", "jJ", "kK", "lL", "öÖ", "äÄ", "'*"},
+		[]string{"<>|", "zZ", "xX", "cC", "vV", "bB", "nN", "mMµ", ",;", ".:", "-_"},
+	))
+)
+
+// layoutRows returns the keys of the number row, the top row, the home row and the bottom row of an ISO keyboard, given
+// the characters of each key in that row. The backslash key is considered part of the home row and the bottom row
+// starts with the key next to the left shift key. Keys that are missing or empty do not produce any characters.
+func layoutRows(numberRow, topRow, homeRow, bottomRow []string) []layoutKey {
+	rows := [][]int{
+		{KeyGrave, Key1, Key2, Key3, Key4, Key5, Key6, Key7, Key8, Key9, Key0, KeyMinus, KeyEqual},
+		{KeyQ, KeyW, KeyE, KeyR, KeyT, KeyY, KeyU, KeyI, KeyO, KeyP, KeyLeftbrace, KeyRightbrace},
+		{KeyA, KeyS, KeyD, KeyF, KeyG, KeyH, KeyJ, KeyK, KeyL, KeySemicolon, KeyApostrophe, KeyBackslash},
+		{Key102Nd, KeyZ, KeyX, KeyC, KeyV, KeyB, KeyN, KeyM, KeyComma, KeyDot, KeySlash},
+	}
+	var keys []layoutKey
+	for i, levels := range [][]string{numberRow, topRow, homeRow, bottomRow} {
+		for j, key := range rows[i] {
+			if j < len(levels) && levels[j] != "" {
+				keys = append(keys, layoutKey{key: key, levels: levels[j]})
+			}
+		}
+	}
+	return keys
+}
@@ -3,8 +3,6 @@ package uinput
 import (
 	"fmt"
 	"io"
-	"os"
-	"syscall"
 )
 
 // A Mouse is a device that will trigger an absolute change event.
@@ -63,18 +61,32 @@ type Mouse interface {
 	// FetchSysPath will return the syspath to the device file.
 	FetchSyspath() (string, error)
 
+	// ReleaseAll will release all buttons that are held down. This is done automatically when the device is closed.
+	ReleaseAll() error
+
+	// Info will return information about the device as seen by the kernel, such as its syspath and event node.
+	Info() (DeviceInfo, error)
+
+	// WriteEvents will write the given events followed by a sync event (SYN_REPORT) as a single frame. This allows to
+	// send events that are not covered by the other methods. All events need to refer to codes that have been
+	// registered for the device, sync events are not allowed.
+	WriteEvents(events ...Event) error
+
 	io.Closer
 }
 
 type vMouse struct {
-	name       []byte
-	deviceFile *os.File
+	name []byte
+	dev  *uinputDevice
+
+	// highResWheel is set if the high resolution wheel axes have been registered (see WithHighResWheel)
+	highResWheel bool
 }
 
 // CreateMouse will create a new mouse input device. A mouse is a device that allows relative input.
 // Relative input means that all changes to the x and y coordinates of the mouse pointer will be
-func CreateMouse(path string, name []byte) (Mouse, error) {
-	err := validateDevicePath(path)
+func CreateMouse(path string, name []byte, opts ...Option) (Mouse, error) {
+	err := validateDevicePath(path, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -83,12 +95,13 @@ func CreateMouse(path string, name []byte) (Mouse, error) {
 		return nil, err
 	}
 
-	fd, err := createMouse(path, name)
+	fd, err := createMouse(path, name, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	return vMouse{name: name, deviceFile: fd}, nil
+	options := newDeviceOptions(opts)
+	return vMouse{name: name, dev: newUinputDevice(fd, mouseCapabilities(options)), highResWheel: options.highResWheel}, nil
 }
 
 // MoveLeft will move the cursor left by the number of pixel specified.
@@ -96,7 +109,7 @@ func (vRel vMouse) MoveLeft(pixel int32) error {
 	if err := assertNotNegative(pixel); err != nil {
 		return err
 	}
-	return sendRelEvent(vRel.deviceFile, relX, -pixel)
+	return sendRelEvent(vRel.dev, RelX, -pixel)
 }
 
 // MoveRight will move the cursor right by the number of pixel specified.
@@ -104,7 +117,7 @@ func (vRel vMouse) MoveRight(pixel int32) error {
 	if err := assertNotNegative(pixel); err != nil {
 		return err
 	}
-	return sendRelEvent(vRel.deviceFile, relX, pixel)
+	return sendRelEvent(vRel.dev, RelX, pixel)
 }
 
 // MoveUp will move the cursor up by the number of pixel specified.
@@ -112,7 +125,7 @@ func (vRel vMouse) MoveUp(pixel int32) error {
 	if err := assertNotNegative(pixel); err != nil {
 		return err
 	}
-	return sendRelEvent(vRel.deviceFile, relY, -pixel)
+	return sendRelEvent(vRel.dev, RelY, -pixel)
 }
 
 // MoveDown will move the cursor down by the number of pixel specified.
@@ -120,172 +133,210 @@ func (vRel vMouse) MoveDown(pixel int32) error {
 	if err := assertNotNegative(pixel); err != nil {
 		return err
 	}
-	return sendRelEvent(vRel.deviceFile, relY, pixel)
+	return sendRelEvent(vRel.dev, RelY, pixel)
 }
 
 // Move will perform a move of the mouse pointer along the x and y axes relative to the current position as requested.
 // Note that the upper left corner is (0, 0), so positive x and y means moving right (x) and down (y), whereas negative
 // values will cause a move towards the upper left corner.
+// Both axes are moved within the same frame, resulting in a single (diagonal) movement.
 func (vRel vMouse) Move(x, y int32) error {
-	if err := sendRelEvent(vRel.deviceFile, relX, x); err != nil {
-		return fmt.Errorf("Failed to move pointer along x axis: %v", err)
-	}
-	if err := sendRelEvent(vRel.deviceFile, relY, y); err != nil {
-		return fmt.Errorf("Failed to move pointer along y axis: %v", err)
+	err := vRel.dev.send(
+		inputEvent{Type: EvRel, Code: RelX, Value: x},
+		inputEvent{Type: EvRel, Code: RelY, Value: y})
+	if err != nil {
+		return fmt.Errorf("Failed to move pointer: %w", err)
 	}
 	return nil
 }
 
 // LeftClick will issue a LeftClick.
 func (vRel vMouse) LeftClick() error {
-	err := sendBtnEvent(vRel.deviceFile, []int{evMouseBtnLeft}, btnStatePressed)
+	err := sendBtnEvent(vRel.dev, ButtonLeft, btnStatePressed)
 	if err != nil {
-		return fmt.Errorf("Failed to issue the LeftClick event: %v", err)
+		return fmt.Errorf("Failed to issue the LeftClick event: %w", err)
 	}
 
-	return sendBtnEvent(vRel.deviceFile, []int{evMouseBtnLeft}, btnStateReleased)
+	return sendBtnEvent(vRel.dev, ButtonLeft, btnStateReleased)
 }
 
 // RightClick will issue a RightClick
 func (vRel vMouse) RightClick() error {
-	err := sendBtnEvent(vRel.deviceFile, []int{evMouseBtnRight}, btnStatePressed)
+	err := sendBtnEvent(vRel.dev, ButtonRight, btnStatePressed)
 	if err != nil {
-		return fmt.Errorf("Failed to issue the RightClick event: %v", err)
+		return fmt.Errorf("Failed to issue the RightClick event: %w", err)
 	}
 
-	return sendBtnEvent(vRel.deviceFile, []int{evMouseBtnRight}, btnStateReleased)
+	return sendBtnEvent(vRel.dev, ButtonRight, btnStateReleased)
 }
 
 // MiddleClick will issue a MiddleClick
 func (vRel vMouse) MiddleClick() error {
-	err := sendBtnEvent(vRel.deviceFile, []int{evMouseBtnMiddle}, btnStatePressed)
+	err := sendBtnEvent(vRel.dev, ButtonMiddle, btnStatePressed)
 	if err != nil {
-		return fmt.Errorf("Failed to issue the MiddleClick event: %v", err)
+		return fmt.Errorf("Failed to issue the MiddleClick event: %w", err)
 	}
 
-	return sendBtnEvent(vRel.deviceFile, []int{evMouseBtnMiddle}, btnStateReleased)
+	return sendBtnEvent(vRel.dev, ButtonMiddle, btnStateReleased)
 }
 
 // LeftPress will simulate a press of the left mouse button. Note that the button will not be released until
 // LeftRelease is invoked.
 func (vRel vMouse) LeftPress() error {
-	return sendBtnEvent(vRel.deviceFile, []int{evMouseBtnLeft}, btnStatePressed)
+	return sendBtnEvent(vRel.dev, ButtonLeft, btnStatePressed)
 }
 
 // LeftRelease will simulate the release of the left mouse button.
 func (vRel vMouse) LeftRelease() error {
-	return sendBtnEvent(vRel.deviceFile, []int{evMouseBtnLeft}, btnStateReleased)
+	return sendBtnEvent(vRel.dev, ButtonLeft, btnStateReleased)
 }
 
 // RightPress will simulate the press of the right mouse button. Note that the button will not be released until
 // RightRelease is invoked.
 func (vRel vMouse) RightPress() error {
-	return sendBtnEvent(vRel.deviceFile, []int{evMouseBtnRight}, btnStatePressed)
+	return sendBtnEvent(vRel.dev, ButtonRight, btnStatePressed)
 }
 
 // RightRelease will simulate the release of the right mouse button.
 func (vRel vMouse) RightRelease() error {
-	return sendBtnEvent(vRel.deviceFile, []int{evMouseBtnRight}, btnStateReleased)
+	return sendBtnEvent(vRel.dev, ButtonRight, btnStateReleased)
 }
 
 // MiddlePress will simulate the press of the middle mouse button. Note that the button will not be released until
 // MiddleRelease is invoked.
 func (vRel vMouse) MiddlePress() error {
-	return sendBtnEvent(vRel.deviceFile, []int{evMouseBtnMiddle}, btnStatePressed)
+	return sendBtnEvent(vRel.dev, ButtonMiddle, btnStatePressed)
 }
 
 // MiddleRelease will simulate the release of the middle mouse button.
 func (vRel vMouse) MiddleRelease() error {
-	return sendBtnEvent(vRel.deviceFile, []int{evMouseBtnMiddle}, btnStateReleased)
+	return sendBtnEvent(vRel.dev, ButtonMiddle, btnStateReleased)
 }
 
-// Wheel will simulate a wheel movement.
+// Wheel will simulate a wheel movement. If the mouse has been created using WithHighResWheel, the movement is reported
+// on the high resolution wheel axis as well, within the same frame.
 func (vRel vMouse) Wheel(horizontal bool, delta int32) error {
-	w := relWheel
+	w, hiRes := uint16(RelWheel), uint16(RelWheelHiRes)
 	if horizontal {
-		w = relHWheel
+		w, hiRes = RelHWheel, RelHWheelHiRes
 	}
-	return sendRelEvent(vRel.deviceFile, uint16(w), delta)
+	if !vRel.highResWheel {
+		return sendRelEvent(vRel.dev, w, delta)
+	}
+	err := vRel.dev.send(
+		inputEvent{Type: EvRel, Code: w, Value: delta},
+		inputEvent{Type: EvRel, Code: hiRes, Value: delta * wheelHiResPerNotch})
+	if err != nil {
+		return fmt.Errorf("failed to write rel event to device file: %w", err)
+	}
+	return nil
+}
+
+// ReleaseAll will release everything that is currently held down, see the interface for details.
+func (vRel vMouse) ReleaseAll() error {
+	return vRel.dev.releaseAll()
+}
+
+// Info will return information about the device as seen by the kernel.
+func (vRel vMouse) Info() (DeviceInfo, error) {
+	return vRel.dev.info()
+}
+
+// WriteEvents will write the given events as a single frame.
+func (vRel vMouse) WriteEvents(events ...Event) error {
+	return vRel.dev.writeEvents(events)
 }
 
 // Close closes the device and releases the device.
 func (vRel vMouse) Close() error {
-	return closeDevice(vRel.deviceFile)
+	return vRel.dev.close()
+}
+
+// wheelHiResPerNotch is the value of the high resolution wheel axes that corresponds to a single notch of the wheel.
+const wheelHiResPerNotch = 120
+
+var (
+	mouseButtons      = []int{ButtonLeft, ButtonRight, ButtonMiddle}
+	mouseAxes         = []int{RelX, RelY, RelWheel, RelHWheel}
+	mouseHighResWheel = []int{RelWheelHiRes, RelHWheelHiRes}
+)
+
+// mouseCapabilities returns the codes registered by createMouse.
+func mouseCapabilities(options deviceOptions) capabilities {
+	caps := make(capabilities)
+	caps.add(EvKey, mouseButtons...)
+	caps.add(EvRel, mouseAxes...)
+	if options.highResWheel {
+		caps.add(EvRel, mouseHighResWheel...)
+	}
+	return caps
 }
 
-func createMouse(path string, name []byte) (fd *os.File, err error) {
-	deviceFile, err := createDeviceFile(path)
+func createMouse(path string, name []byte, opts []Option) (fd backend, err error) {
+	deviceFile, err := createDeviceFile(path, opts)
 	if err != nil {
-		return nil, fmt.Errorf("could not create relative axis input device: %v", err)
+		return nil, fmt.Errorf("could not create relative axis input device: %w", err)
 	}
 
-	err = registerDevice(deviceFile, uintptr(evKey))
+	err = registerDevice(deviceFile, uintptr(EvKey))
 	if err != nil {
-		deviceFile.Close()
-		return nil, fmt.Errorf("failed to register key device: %v", err)
+		// registerDevice closes the device file on failure
+		return nil, fmt.Errorf("failed to register key device: %w", err)
 	}
 
 	// register button events (in order to enable left, right and middle click)
-	for _, event := range []int{evMouseBtnLeft, evMouseBtnRight, evMouseBtnMiddle} {
+	for _, event := range mouseButtons {
 		err = ioctl(deviceFile, uiSetKeyBit, uintptr(event))
 		if err != nil {
 			deviceFile.Close()
-			return nil, fmt.Errorf("failed to register click event %v: %v", event, err)
+			return nil, fmt.Errorf("failed to register click event %v: %w", event, err)
 		}
 	}
 
-	err = registerDevice(deviceFile, uintptr(evRel))
+	err = registerDevice(deviceFile, uintptr(EvRel))
 	if err != nil {
-		deviceFile.Close()
-		return nil, fmt.Errorf("failed to register relative axis input device: %v", err)
+		// registerDevice closes the device file on failure
+		return nil, fmt.Errorf("failed to register relative axis input device: %w", err)
 	}
 
 	// register relative events
-	for _, event := range []int{relX, relY, relWheel, relHWheel} {
+	axes := mouseAxes
+	if newDeviceOptions(opts).highResWheel {
+		axes = append(append([]int(nil), mouseAxes...), mouseHighResWheel...)
+	}
+	for _, event := range axes {
 		err = ioctl(deviceFile, uiSetRelBit, uintptr(event))
 		if err != nil {
 			deviceFile.Close()
-			return nil, fmt.Errorf("failed to register relative event %v: %v", event, err)
+			return nil, fmt.Errorf("failed to register relative event %v: %w", event, err)
 		}
 	}
 
 	return createUsbDevice(deviceFile,
-		uinputUserDev{
-			Name: toUinputName(name),
-			ID: inputID{
+		deviceSetup{
+			name: name,
+			id: inputID{
 				Bustype: busUsb,
 				Vendor:  0x4711,
 				Product: 0x0816,
-				Version: 1}})
+				Version: 1}}, opts)
 }
 
-func sendRelEvent(deviceFile *os.File, eventCode uint16, pixel int32) error {
-	iev := inputEvent{
-		Time:  syscall.Timeval{Sec: 0, Usec: 0},
-		Type:  evRel,
-		Code:  eventCode,
-		Value: pixel}
-
-	buf, err := inputEventToBuffer(iev)
+func sendRelEvent(dev *uinputDevice, eventCode uint16, pixel int32) error {
+	err := dev.send(inputEvent{Type: EvRel, Code: eventCode, Value: pixel})
 	if err != nil {
-		return fmt.Errorf("writing abs event failed: %v", err)
+		return fmt.Errorf("failed to write rel event to device file: %w", err)
 	}
-
-	_, err = deviceFile.Write(buf)
-	if err != nil {
-		return fmt.Errorf("failed to write rel event to device file: %v", err)
-	}
-
-	return syncEvents(deviceFile)
+	return nil
 }
 
 func assertNotNegative(val int32) error {
 	if val < 0 {
-		return fmt.Errorf("%v is out of range. Expected a positive or zero value", val)
+		return axisError("%v is out of range. Expected a positive or zero value", val)
 	}
 	return nil
 }
 
 func (vRel vMouse) FetchSyspath() (string, error) {
-	return fetchSyspath(vRel.deviceFile)
+	return vRel.dev.fetchSyspath()
 }
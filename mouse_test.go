@@ -1,9 +1,11 @@
 package uinput
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"reflect"
 	"testing"
 )
 
@@ -157,8 +159,8 @@ func TestMouseCreationFailsOnEmptyPath(t *testing.T) {
 func TestMouseCreationFailsOnNonExistentPathName(t *testing.T) {
 	path := "/some/bogus/path"
 	_, err := CreateMouse(path, []byte("MouseDevice"))
-	if !os.IsNotExist(err) {
-		t.Fatalf("Expected: os.IsNotExist error\nActual: %s", err)
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Expected: os.ErrNotExist error\nActual: %s", err)
 	}
 }
 
@@ -169,7 +171,7 @@ func TestMouseCreationFailsOnWrongPathName(t *testing.T) {
 	}
 	defer file.Close()
 
-	expected := "failed to register key device: failed to close device: inappropriate ioctl for device"
+	expected := "failed to register key device: invalid file handle returned from ioctl: inappropriate ioctl for device (failed to close device: inappropriate ioctl for device)"
 	_, err = CreateMouse(file.Name(), []byte("DialDevice"))
 	if err == nil || !(expected == err.Error()) {
 		t.Fatalf("Expected: %s\nActual: %s", expected, err)
@@ -440,3 +442,72 @@ func TestMouseSyspath(t *testing.T) {
 	}
 	t.Logf("Syspath: %s", sysPath)
 }
+
+func TestMouseWheelReportsHighResolutionEvents(t *testing.T) {
+	fake := NewFakeBackend()
+	vm, err := CreateMouse("/dev/uinput", []byte("Test Mouse"), WithFakeBackend(fake), WithHighResWheel())
+	if err != nil {
+		t.Fatalf("Failed to create the virtual mouse. Last error was: %s\n", err)
+	}
+	defer vm.Close()
+
+	err = vm.Wheel(false, -2)
+	if err != nil {
+		t.Fatalf("Failed to send wheel event. Last error was: %s\n", err)
+	}
+	err = vm.Wheel(true, 1)
+	if err != nil {
+		t.Fatalf("Failed to send wheel event. Last error was: %s\n", err)
+	}
+	// half a notch may only be sent using the high resolution axis
+	err = vm.WriteEvents(Event{Type: EvRel, Code: RelWheelHiRes, Value: 60})
+	if err != nil {
+		t.Fatalf("Failed to write events. Last error was: %s\n", err)
+	}
+
+	expected := []Event{
+		{Type: EvRel, Code: RelWheel, Value: -2}, {Type: EvRel, Code: RelWheelHiRes, Value: -240}, {Type: EvSyn, Code: SynReport},
+		{Type: EvRel, Code: RelHWheel, Value: 1}, {Type: EvRel, Code: RelHWheelHiRes, Value: 120}, {Type: EvSyn, Code: SynReport},
+		{Type: EvRel, Code: RelWheelHiRes, Value: 60}, {Type: EvSyn, Code: SynReport},
+	}
+	if actual := fake.Events(); !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, actual)
+	}
+}
+
+func TestMouseWheelReportsRegularEventsByDefault(t *testing.T) {
+	fake := NewFakeBackend()
+	vm, err := CreateMouse("/dev/uinput", []byte("Test Mouse"), WithFakeBackend(fake))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual mouse. Last error was: %s\n", err)
+	}
+	defer vm.Close()
+
+	err = vm.Wheel(false, -2)
+	if err != nil {
+		t.Fatalf("Failed to send wheel event. Last error was: %s\n", err)
+	}
+	err = vm.WriteEvents(Event{Type: EvRel, Code: RelWheelHiRes, Value: 60})
+	if !errors.Is(err, ErrAxisOutOfRange) {
+		t.Fatalf("Expected the high resolution wheel to be rejected by default, but got: %v", err)
+	}
+
+	expected := []Event{{Type: EvRel, Code: RelWheel, Value: -2}, {Type: EvSyn, Code: SynReport}}
+	if actual := fake.Events(); !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, actual)
+	}
+}
+
+func BenchmarkMouseMove(b *testing.B) {
+	devNull := openDevNull(b)
+	defer devNull.Close()
+	vm := vMouse{dev: newUinputDevice(&fileBackend{file: devNull}, mouseCapabilities(newDeviceOptions(nil)))}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		err := vm.Move(10, -10)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -3,7 +3,6 @@ package uinput
 import (
 	"fmt"
 	"io"
-	"os"
 )
 
 // MultiTouch is an input device that uses absolute axis events.
@@ -20,13 +19,24 @@ type MultiTouch interface {
 	// FetchSyspath will return the syspath to the device file.
 	FetchSyspath() (string, error)
 
+	// ReleaseAll will release all contacts that touch the surface. This is done automatically when the device is closed.
+	ReleaseAll() error
+
+	// Info will return information about the device as seen by the kernel, such as its syspath and event node.
+	Info() (DeviceInfo, error)
+
+	// WriteEvents will write the given events followed by a sync event (SYN_REPORT) as a single frame. This allows to
+	// send events that are not covered by the other methods. All events need to refer to codes that have been
+	// registered for the device, sync events are not allowed.
+	WriteEvents(events ...Event) error
+
 	io.Closer
 }
 
 type vMultiTouch struct {
-	name       []byte
-	deviceFile *os.File
-	contacts   []multiTouchContact
+	name     []byte
+	dev      *uinputDevice
+	contacts []multiTouchContact
 }
 
 // The contact can be described as a finger contacting the surface of the MultiTouch device.
@@ -38,8 +48,8 @@ type multiTouchContact struct {
 
 // CreateMultiTouch will create a new multitouch device. Note that you will need to define the x and y-axis boundaries
 // (min and max) within which the contacs maybe moved around, as well as the maximum amount of contacts allowed.
-func CreateMultiTouch(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32, maxContacts int32) (MultiTouch, error) {
-	err := validateDevicePath(path)
+func CreateMultiTouch(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32, maxContacts int32, opts ...Option) (MultiTouch, error) {
+	err := validateDevicePath(path, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -48,12 +58,12 @@ func CreateMultiTouch(path string, name []byte, minX int32, maxX int32, minY int
 		return nil, err
 	}
 
-	fd, err := createMultiTouch(path, name, minX, maxX, minY, maxY, maxContacts)
+	fd, err := createMultiTouch(path, name, minX, maxX, minY, maxY, maxContacts, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	var multitouch vMultiTouch = vMultiTouch{name: name, deviceFile: fd}
+	var multitouch vMultiTouch = vMultiTouch{name: name, dev: newUinputDevice(fd, multiTouchCapabilities())}
 
 	for i := int32(0); i < maxContacts; i++ {
 		multitouch.contacts = append(multitouch.contacts, multiTouchContact{slot: i, multitouch: &multitouch})
@@ -67,74 +77,88 @@ func (vMulti vMultiTouch) GetContacts() []multiTouchContact {
 }
 
 func (vMulti vMultiTouch) FetchSyspath() (string, error) {
-	return fetchSyspath(vMulti.deviceFile)
+	return vMulti.dev.fetchSyspath()
+}
+
+// ReleaseAll will release everything that is currently held down, see the interface for details.
+func (vMulti vMultiTouch) ReleaseAll() error {
+	return vMulti.dev.releaseAll()
+}
+
+// Info will return information about the device as seen by the kernel.
+func (vMulti vMultiTouch) Info() (DeviceInfo, error) {
+	return vMulti.dev.info()
+}
+
+// WriteEvents will write the given events as a single frame.
+func (vMulti vMultiTouch) WriteEvents(events ...Event) error {
+	return vMulti.dev.writeEvents(events)
 }
 
 func (vMulti vMultiTouch) Close() error {
-	return closeDevice(vMulti.deviceFile)
+	return vMulti.dev.close()
+}
+
+var (
+	multiTouchButtons = []int{ButtonTouch}
+	multiTouchAxes    = []int{AbsMtSlot, AbsMtTrackingID, AbsMtPositionX, AbsMtPositionY}
+)
+
+// multiTouchCapabilities returns the codes registered by createMultiTouch.
+func multiTouchCapabilities() capabilities {
+	caps := make(capabilities)
+	caps.add(EvKey, multiTouchButtons...)
+	caps.add(EvAbs, multiTouchAxes...)
+	return caps
 }
 
-func createMultiTouch(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32, maxContacts int32) (fd *os.File, err error) {
-	deviceFile, err := createDeviceFile(path)
+func createMultiTouch(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32, maxContacts int32, opts []Option) (fd backend, err error) {
+	deviceFile, err := createDeviceFile(path, opts)
 	if err != nil {
-		return nil, fmt.Errorf("could not create absolute axis input device: %v", err)
+		return nil, fmt.Errorf("could not create absolute axis input device: %w", err)
 	}
 
-	err = registerDevice(deviceFile, uintptr(evKey))
+	err = registerDevice(deviceFile, uintptr(EvKey))
 	if err != nil {
-		_ = deviceFile.Close()
-		return nil, fmt.Errorf("failed to register key device: %v", err)
+		// registerDevice closes the device file on failure
+		return nil, fmt.Errorf("failed to register key device: %w", err)
 	}
 
-	for _, event := range []int{evBtnTouch} {
+	for _, event := range multiTouchButtons {
 		err = ioctl(deviceFile, uiSetKeyBit, uintptr(event))
 		if err != nil {
 			_ = deviceFile.Close()
-			return nil, fmt.Errorf("failed to register button event %v: %v", event, err)
+			return nil, fmt.Errorf("failed to register button event %v: %w", event, err)
 		}
 	}
 
-	err = registerDevice(deviceFile, uintptr(evAbs))
+	err = registerDevice(deviceFile, uintptr(EvAbs))
 	if err != nil {
-		_ = deviceFile.Close()
-		return nil, fmt.Errorf("failed to register absolute axis input device: %v", err)
+		// registerDevice closes the device file on failure
+		return nil, fmt.Errorf("failed to register absolute axis input device: %w", err)
 	}
 
-	for _, event := range []int{
-		absMtSlot,
-		absMtTrackingId,
-		absMtPositionX,
-		absMtPositionY,
-	} {
+	for _, event := range multiTouchAxes {
 		err = ioctl(deviceFile, uiSetAbsBit, uintptr(event))
 		if err != nil {
 			_ = deviceFile.Close()
-			return nil, fmt.Errorf("failed to register absolute axis event %v: %v", event, err)
+			return nil, fmt.Errorf("failed to register absolute axis event %v: %w", event, err)
 		}
 	}
 
-	var absMin [absSize]int32
-	absMin[absMtPositionX] = minX
-	absMin[absMtPositionY] = minY
-	absMin[absMtTrackingId] = 0x00
-	absMin[absMtSlot] = 0x00
-
-	var absMax [absSize]int32
-	absMax[absMtPositionX] = maxX
-	absMax[absMtPositionY] = maxY
-	absMax[absMtTrackingId] = maxContacts
-	absMax[absMtSlot] = maxContacts
-
 	return createUsbDevice(deviceFile,
-		uinputUserDev{
-			Name: toUinputName(name),
-			ID: inputID{
+		deviceSetup{
+			name: name,
+			id: inputID{
 				Bustype: busUsb,
 				Vendor:  0x0,
 				Product: 0x0,
 				Version: 0},
-			Absmin: absMin,
-			Absmax: absMax})
+			absAxes: []absAxis{
+				{code: AbsMtSlot, min: 0x00, max: maxContacts},
+				{code: AbsMtTrackingID, min: 0x00, max: maxContacts},
+				{code: AbsMtPositionX, min: minX, max: maxX},
+				{code: AbsMtPositionY, min: minY, max: maxY}}}, opts)
 }
 
 // The contact will be held down at the coordinates specified
@@ -142,8 +166,8 @@ func (c multiTouchContact) TouchDownAt(x int32, y int32) error {
 	var events []inputEvent
 
 	events = append(events, inputEvent{
-		Type:  evAbs,
-		Code:  absMtPositionX,
+		Type:  EvAbs,
+		Code:  AbsMtPositionX,
 		Value: x,
 	})
 
@@ -152,8 +176,8 @@ func (c multiTouchContact) TouchDownAt(x int32, y int32) error {
 	}
 
 	events = append(events, inputEvent{
-		Type:  evAbs,
-		Code:  absMtPositionY,
+		Type:  EvAbs,
+		Code:  AbsMtPositionY,
 		Value: y,
 	})
 
@@ -172,14 +196,14 @@ func (c multiTouchContact) sendAbsEvent(events []inputEvent) error {
 	var ev []inputEvent
 
 	ev = append(ev, inputEvent{
-		Type:  evAbs,
-		Code:  absMtSlot,
+		Type:  EvAbs,
+		Code:  AbsMtSlot,
 		Value: c.slot,
 	})
 
 	ev = append(ev, inputEvent{
-		Type:  evAbs,
-		Code:  absMtTrackingId,
+		Type:  EvAbs,
+		Code:  AbsMtTrackingID,
 		Value: c.tracking_id,
 	})
 
@@ -187,17 +211,9 @@ func (c multiTouchContact) sendAbsEvent(events []inputEvent) error {
 		ev = append(ev, events...)
 	}
 
-	for _, iev := range ev {
-		buf, err := inputEventToBuffer(iev)
-		if err != nil {
-			return fmt.Errorf("writing abs event failed: %v", err)
-		}
-
-		_, err = c.multitouch.deviceFile.Write(buf)
-		if err != nil {
-			return fmt.Errorf("failed to write abs event to device file: %v", err)
-		}
+	err := c.multitouch.dev.send(ev...)
+	if err != nil {
+		return fmt.Errorf("failed to write abs event to device file: %w", err)
 	}
-
-	return syncEvents(c.multitouch.deviceFile)
+	return nil
 }
@@ -0,0 +1,211 @@
+package uinput
+
+import (
+	"context"
+	"time"
+)
+
+// defaultReadyTimeout is the maximum amount of time to wait for a newly created device to become usable, unless a
+// different timeout or a context has been specified.
+const defaultReadyTimeout = 5 * time.Second
+
+// An Option configures the creation of a device. Options may be passed to all functions that create a device.
+type Option func(*deviceOptions)
+
+// BusType is the type of bus a device claims to be attached to.
+type BusType uint16
+
+const (
+	BusPCI       BusType = 0x01
+	BusUSB       BusType = 0x03
+	BusBluetooth BusType = 0x05
+	BusVirtual   BusType = 0x06
+	BusI8042     BusType = 0x11
+	BusRS232     BusType = 0x13
+	BusI2C       BusType = 0x18
+	BusHost      BusType = 0x19
+	BusSPI       BusType = 0x1c
+)
+
+// Property is an input property (INPUT_PROP_*), which gives applications like libinput a hint on how to interpret the
+// events of a device.
+type Property int
+
+const (
+	// PropPointer marks a device that requires an on-screen pointer, e.g. a touch pad.
+	PropPointer Property = 0x00
+	// PropDirect marks a device whose coordinates map directly to the screen, e.g. a touch screen.
+	PropDirect Property = 0x01
+	// PropButtonpad marks a touch pad that has its button below the surface.
+	PropButtonpad Property = 0x02
+	// PropSemiMT marks a touch pad that only reports the bounding box of all contacts.
+	PropSemiMT Property = 0x03
+	// PropTopButtonpad marks a touch pad with software buttons on top of the surface.
+	PropTopButtonpad Property = 0x04
+	// PropPointingStick marks a pointing stick.
+	PropPointingStick Property = 0x05
+	// PropAccelerometer marks a device that reports acceleration along its axes.
+	PropAccelerometer Property = 0x06
+
+	propMax = 0x1f
+)
+
+type deviceOptions struct {
+	ctx          context.Context
+	readyTimeout time.Duration
+
+	// the identity of the device overrides the defaults of the respective device type if set
+	busType *BusType
+	vendor  *uint16
+	product *uint16
+	version *uint16
+
+	phys       string
+	properties []Property
+
+	// layout and unicodeInputs are used by Keyboard.Type
+	layout        Layout
+	unicodeInputs []UnicodeInput
+
+	// scanCodes and highResWheel register additional codes for keyboards and mice
+	scanCodes    bool
+	highResWheel bool
+
+	fake *FakeBackend
+}
+
+func newDeviceOptions(opts []Option) deviceOptions {
+	options := deviceOptions{ctx: context.Background(), readyTimeout: defaultReadyTimeout, layout: LayoutUS}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// WithContext sets the context that bounds the creation of the device. Once the context is done, creation is aborted
+// and the context's error is returned. Note that the ready timeout still applies (see WithReadyTimeout).
+func WithContext(ctx context.Context) Option {
+	return func(o *deviceOptions) {
+		o.ctx = ctx
+	}
+}
+
+// WithReadyTimeout sets the maximum amount of time to wait for the event node of a newly created device to become
+// available (5 seconds by default). A timeout of zero disables the timeout, which leaves the context as the only bound.
+func WithReadyTimeout(timeout time.Duration) Option {
+	return func(o *deviceOptions) {
+		o.readyTimeout = timeout
+	}
+}
+
+// readyContext returns the context that bounds the wait for the device to become ready.
+func (o deviceOptions) readyContext() (context.Context, context.CancelFunc) {
+	if o.readyTimeout > 0 {
+		return context.WithTimeout(o.ctx, o.readyTimeout)
+	}
+	return context.WithCancel(o.ctx)
+}
+
+// WithBusType sets the bus type of the device (BusUSB by default).
+func WithBusType(busType BusType) Option {
+	return func(o *deviceOptions) {
+		o.busType = &busType
+	}
+}
+
+// WithVendor sets the vendor id of the device.
+func WithVendor(vendor uint16) Option {
+	return func(o *deviceOptions) {
+		o.vendor = &vendor
+	}
+}
+
+// WithProduct sets the product id of the device.
+func WithProduct(product uint16) Option {
+	return func(o *deviceOptions) {
+		o.product = &product
+	}
+}
+
+// WithVersion sets the version of the device.
+func WithVersion(version uint16) Option {
+	return func(o *deviceOptions) {
+		o.version = &version
+	}
+}
+
+// applyIdentity overrides the default identity of a device with all identity options that have been set.
+func (o deviceOptions) applyIdentity(id *inputID) {
+	if o.busType != nil {
+		id.Bustype = uint16(*o.busType)
+	}
+	if o.vendor != nil {
+		id.Vendor = *o.vendor
+	}
+	if o.product != nil {
+		id.Product = *o.product
+	}
+	if o.version != nil {
+		id.Version = *o.version
+	}
+}
+
+// WithPhys sets the physical path of the device, which may be used to group related devices. Note that the unique
+// identifier (uniq) of a device can not be set, since uinput does not offer a way of doing so.
+func WithPhys(phys string) Option {
+	return func(o *deviceOptions) {
+		o.phys = phys
+	}
+}
+
+// WithProperties sets the given input properties on the device, for example PropDirect for a touch screen.
+func WithProperties(properties ...Property) Option {
+	return func(o *deviceOptions) {
+		o.properties = append(o.properties, properties...)
+	}
+}
+
+// WithLayout sets the keyboard layout that Keyboard.Type uses to translate text into key presses (LayoutUS by
+// default). The layout needs to match the layout the system uses for the keyboard, which can not be detected by the
+// device itself. The option is ignored by all other devices.
+func WithLayout(layout Layout) Option {
+	return func(o *deviceOptions) {
+		o.layout = layout
+	}
+}
+
+// WithUnicodeInput sets the input methods that Keyboard.Type uses for characters that are not part of the layout of
+// the keyboard, for example emoji. The input methods are tried in the given order. By default, no input method is used,
+// since all of them depend on the software that receives the input. The option is ignored by all other devices.
+func WithUnicodeInput(inputs ...UnicodeInput) Option {
+	return func(o *deviceOptions) {
+		o.unicodeInputs = inputs
+	}
+}
+
+// WithScanCodes registers scan codes (MSC_SCAN) for a keyboard, so that they may be sent along with key events using
+// WriteEvents, just like physical keyboards report the scan code of each key. The option is ignored by all other
+// devices.
+func WithScanCodes() Option {
+	return func(o *deviceOptions) {
+		o.scanCodes = true
+	}
+}
+
+// WithHighResWheel registers the high resolution wheel axes (REL_WHEEL_HI_RES and REL_HWHEEL_HI_RES) for a mouse, which
+// allows to scroll by fractions of a notch using WriteEvents. Since libinput ignores the regular wheel axes of such
+// devices, Mouse.Wheel then reports each movement on both axes, with 120 units per notch on the high resolution axis.
+// The option is ignored by all other devices.
+func WithHighResWheel() Option {
+	return func(o *deviceOptions) {
+		o.highResWheel = true
+	}
+}
+
+// WithFakeBackend creates the device using the given fake backend instead of the uinput device file. The device path
+// is ignored in this case. This allows to test code that uses this package without access to /dev/uinput.
+func WithFakeBackend(fake *FakeBackend) Option {
+	return func(o *deviceOptions) {
+		o.fake = fake
+	}
+}
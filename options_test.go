@@ -0,0 +1,118 @@
+package uinput
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+func TestIdentityOptionsOverrideDefaults(t *testing.T) {
+	id := inputID{Bustype: busUsb, Vendor: 0x4711, Product: 0x0816, Version: 1}
+	newDeviceOptions([]Option{WithBusType(BusBluetooth), WithProduct(0x0819)}).applyIdentity(&id)
+
+	expected := inputID{Bustype: 0x05, Vendor: 0x4711, Product: 0x0819, Version: 1}
+	if id != expected {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, id)
+	}
+}
+
+func TestIdentityIsUnchangedWithoutOptions(t *testing.T) {
+	id := inputID{Bustype: busUsb, Vendor: 0x4711, Product: 0x0816, Version: 1}
+	newDeviceOptions(nil).applyIdentity(&id)
+
+	expected := inputID{Bustype: busUsb, Vendor: 0x4711, Product: 0x0816, Version: 1}
+	if id != expected {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, id)
+	}
+}
+
+func TestDialIdentityCanBeSet(t *testing.T) {
+	dial, err := CreateDial("/dev/uinput", []byte("Test Dial"),
+		WithBusType(BusVirtual), WithVendor(0x1234), WithProduct(0x5678), WithVersion(0x0002))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual dial. Last error was: %s\n", err)
+	}
+	defer dial.Close()
+
+	sysPath, err := fetchSyspath(dial.(vDial).dev.file)
+	if err != nil {
+		t.Fatalf("Failed to fetch syspath. Last error was: %s\n", err)
+	}
+
+	for file, expected := range map[string]string{
+		"bustype": "0006",
+		"vendor":  "1234",
+		"product": "5678",
+		"version": "0002",
+	} {
+		assertSysfsAttribute(t, sysPath, filepath.Join("id", file), expected)
+	}
+}
+
+func TestSetPhysRequestMatchesPointerSize(t *testing.T) {
+	expected := uintptr(0x4008556c)
+	if unsafe.Sizeof(uintptr(0)) == 4 {
+		expected = 0x4004556c
+	}
+	if uiSetPhys != expected {
+		t.Fatalf("Expected: %#x\nActual: %#x", expected, uintptr(uiSetPhys))
+	}
+}
+
+func TestInvalidPropertyIsRejected(t *testing.T) {
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to open %s: %v", os.DevNull, err)
+	}
+	defer devNull.Close()
+
+	expected := "input property 32 is not in range (maximum is 31)"
+	err = setPhysAndProperties(&fileBackend{file: devNull}, newDeviceOptions([]Option{WithProperties(propMax + 1)}))
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected: %s\nActual: %v", expected, err)
+	}
+}
+
+func TestTouchPadPhysAndProperties(t *testing.T) {
+	touchPad, err := CreateTouchPad("/dev/uinput", []byte("Test TouchPad"), 0, 1024, 0, 768,
+		WithPhys("uinput-test/input0"), WithProperties(PropPointer, PropButtonpad))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual touch pad. Last error was: %s\n", err)
+	}
+	defer touchPad.Close()
+
+	sysPath, err := touchPad.FetchSyspath()
+	if err != nil {
+		t.Fatalf("Failed to fetch syspath. Last error was: %s\n", err)
+	}
+	assertSysfsAttribute(t, sysPath, "phys", "uinput-test/input0")
+	assertSysfsAttribute(t, sysPath, "properties", "5")
+}
+
+func TestMultiTouchIsDirect(t *testing.T) {
+	multiTouch, err := CreateMultiTouch("/dev/uinput", []byte("Test MultiTouch"), 0, 1024, 0, 768, 2,
+		WithProperties(PropDirect))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual multi touch device. Last error was: %s\n", err)
+	}
+	defer multiTouch.Close()
+
+	sysPath, err := multiTouch.FetchSyspath()
+	if err != nil {
+		t.Fatalf("Failed to fetch syspath. Last error was: %s\n", err)
+	}
+	assertSysfsAttribute(t, sysPath, "properties", "2")
+}
+
+func assertSysfsAttribute(t *testing.T, sysPath string, attribute string, expected string) {
+	actual, err := ioutil.ReadFile(filepath.Join(sysPath, attribute))
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", attribute, err)
+	}
+	if strings.TrimSpace(string(actual)) != expected {
+		t.Fatalf("Expected %s to be %s, but got %s", attribute, expected, actual)
+	}
+}
@@ -0,0 +1,76 @@
+package uinput
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	sysInputDir = "/sys/devices/virtual/input"
+	devInputDir = "/dev/input"
+
+	// readyPollInterval is the interval at which sysfs and /dev/input are checked for the event node of a new device.
+	readyPollInterval = 2 * time.Millisecond
+
+	// legacyReadyDelay is the delay used on kernels that cannot report the sysfs node of a device (prior to
+	// linux 3.15), since there is no way of telling which event node belongs to the device.
+	legacyReadyDelay = 200 * time.Millisecond
+)
+
+// waitUntilReady blocks until the event node of the newly created device exists and can be opened, which is the
+// point at which applications (and the X server or compositor, in particular) may pick up the device.
+func waitUntilReady(ctx context.Context, deviceFile backend) error {
+	sysname, err := fetchSysname(deviceFile)
+	if err != nil {
+		select {
+		case <-time.After(legacyReadyDelay):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return waitForEventNode(ctx, filepath.Join(sysInputDir, sysname), devInputDir)
+}
+
+// waitForEventNode polls the given sysfs directory of an input device until it lists an event node, which can be
+// opened in devDir.
+func waitForEventNode(ctx context.Context, syspath string, devDir string) error {
+	ticker := time.NewTicker(readyPollInterval)
+	defer ticker.Stop()
+	for {
+		if eventNodeReady(syspath, devDir) {
+			return nil
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return fmt.Errorf("event node of %s did not become available: %w", syspath, ctx.Err())
+		}
+	}
+}
+
+func eventNodeReady(syspath string, devDir string) bool {
+	entries, err := ioutil.ReadDir(syspath)
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), "event") {
+			continue
+		}
+		node, err := os.OpenFile(filepath.Join(devDir, entry.Name()), syscall.O_RDONLY|syscall.O_NONBLOCK, 0)
+		if err != nil {
+			// the node exists, but may only be read by privileged users (which is perfectly fine)
+			return os.IsPermission(err)
+		}
+		_ = node.Close()
+		return true
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package uinput
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWaitForEventNodeReturnsOnceNodeIsAvailable(t *testing.T) {
+	sysDir, devDir := readyTestDirs(t)
+	defer os.RemoveAll(sysDir)
+	defer os.RemoveAll(devDir)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		_ = os.Mkdir(filepath.Join(sysDir, "event42"), 0755)
+		time.Sleep(20 * time.Millisecond)
+		_ = ioutil.WriteFile(filepath.Join(devDir, "event42"), nil, 0644)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := waitForEventNode(ctx, sysDir, devDir)
+	if err != nil {
+		t.Fatalf("Expected event node to become ready, but got: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(devDir, "event42")); err != nil {
+		t.Fatalf("Expected wait to end only after the event node had been created")
+	}
+}
+
+func TestWaitForEventNodeIsBoundByContext(t *testing.T) {
+	sysDir, devDir := readyTestDirs(t)
+	defer os.RemoveAll(sysDir)
+	defer os.RemoveAll(devDir)
+
+	// the device has been registered, but its node has not been created (yet)
+	err := os.Mkdir(filepath.Join(sysDir, "event7"), 0755)
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create directory: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = waitForEventNode(ctx, sysDir, devDir)
+	if err == nil {
+		t.Fatalf("Expected wait to fail once the context is done")
+	}
+}
+
+func TestReadyTimeoutOption(t *testing.T) {
+	options := newDeviceOptions(nil)
+	if options.readyTimeout != defaultReadyTimeout {
+		t.Fatalf("Expected default timeout of %v, but got %v", defaultReadyTimeout, options.readyTimeout)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	options = newDeviceOptions([]Option{WithContext(ctx), WithReadyTimeout(0)})
+	readyCtx, readyCancel := options.readyContext()
+	defer readyCancel()
+	if _, ok := readyCtx.Deadline(); ok {
+		t.Fatalf("Expected no deadline when the timeout is disabled")
+	}
+	cancel()
+	select {
+	case <-readyCtx.Done():
+	case <-time.After(time.Second):
+		t.Fatalf("Expected ready context to be done once the parent context is cancelled")
+	}
+}
+
+func readyTestDirs(t *testing.T) (string, string) {
+	sysDir, err := ioutil.TempDir("", "uinput-sys-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create directory: %v", err)
+	}
+	devDir, err := ioutil.TempDir("", "uinput-dev-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create directory: %v", err)
+	}
+	return sysDir, devDir
+}
@@ -0,0 +1,69 @@
+package uinput
+
+// deviceState keeps track of everything that is currently active on a device: keys and buttons that are held down,
+// axes that have been moved away from their rest position and touch contacts that touch the surface. This allows to
+// release all of them at once, so that no key remains stuck once the device is closed.
+type deviceState struct {
+	keys [keyCodeMax/64 + 1]uint64
+
+	// restAxes lists the absolute axes that return to 0 once they are no longer actuated (sticks and hats).
+	restAxes  []uint16
+	absValues [absMax + 1]int32
+
+	slot     int32
+	contacts map[int32]bool
+}
+
+// track updates the state according to an event that is sent to the device.
+func (s *deviceState) track(evType uint16, code uint16, value int32) {
+	switch evType {
+	case EvKey:
+		if int(code) > keyCodeMax {
+			return
+		}
+		if value != btnStateReleased {
+			s.keys[code/64] |= 1 << (code % 64)
+		} else {
+			s.keys[code/64] &^= 1 << (code % 64)
+		}
+	case EvAbs:
+		if int(code) > absMax {
+			return
+		}
+		s.absValues[code] = value
+		switch code {
+		case AbsMtSlot:
+			s.slot = value
+		case AbsMtTrackingID:
+			if value == -1 {
+				delete(s.contacts, s.slot)
+				return
+			}
+			if s.contacts == nil {
+				s.contacts = make(map[int32]bool)
+			}
+			s.contacts[s.slot] = true
+		}
+	}
+}
+
+// release passes the events that are required to release everything that is currently active on to add.
+func (s *deviceState) release(add func(evType uint16, code uint16, value int32)) {
+	for slot := range s.contacts {
+		add(EvAbs, AbsMtSlot, slot)
+		add(EvAbs, AbsMtTrackingID, -1)
+	}
+	for _, code := range s.restAxes {
+		if s.absValues[code] != 0 {
+			add(EvAbs, code, 0)
+		}
+	}
+	for i, word := range s.keys {
+		for bit := uint16(0); word != 0; bit++ {
+			if word&1 != 0 {
+				add(EvKey, uint16(i*64)+bit, btnStateReleased)
+			}
+			word >>= 1
+		}
+	}
+}
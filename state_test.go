@@ -0,0 +1,111 @@
+package uinput
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestReleaseCoversKeysAxesAndContacts(t *testing.T) {
+	s := deviceState{restAxes: gamepadRestAxes}
+	for _, ev := range []inputEvent{
+		{Type: EvKey, Code: KeyLeftctrl, Value: btnStatePressed},
+		{Type: EvKey, Code: ButtonSouth, Value: btnStatePressed},
+		{Type: EvKey, Code: KeyA, Value: btnStatePressed},
+		{Type: EvKey, Code: KeyA, Value: btnStateReleased},
+		{Type: EvAbs, Code: AbsX, Value: 100},
+		{Type: EvAbs, Code: AbsY, Value: 100},
+		{Type: EvAbs, Code: AbsY, Value: 0},
+		{Type: EvAbs, Code: AbsHat0X, Value: -1},
+		{Type: EvAbs, Code: AbsMtSlot, Value: 0},
+		{Type: EvAbs, Code: AbsMtTrackingID, Value: 0},
+		{Type: EvAbs, Code: AbsMtSlot, Value: 1},
+		{Type: EvAbs, Code: AbsMtTrackingID, Value: 1},
+		{Type: EvAbs, Code: AbsMtSlot, Value: 0},
+		{Type: EvAbs, Code: AbsMtTrackingID, Value: -1},
+	} {
+		s.track(ev.Type, ev.Code, ev.Value)
+	}
+
+	expected := []inputEvent{
+		{Type: EvAbs, Code: AbsMtSlot, Value: 1},
+		{Type: EvAbs, Code: AbsMtTrackingID, Value: -1},
+		{Type: EvAbs, Code: AbsX, Value: 0},
+		{Type: EvAbs, Code: AbsHat0X, Value: 0},
+		{Type: EvKey, Code: KeyLeftctrl, Value: btnStateReleased},
+		{Type: EvKey, Code: ButtonSouth, Value: btnStateReleased},
+	}
+	if actual := releaseEvents(&s); !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, actual)
+	}
+	if actual := releaseEvents(&s); len(actual) != 0 {
+		t.Fatalf("Expected nothing to be released a second time, but got: %+v", actual)
+	}
+}
+
+func TestCloseReleasesHeldKeys(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create pipe: %v", err)
+	}
+	defer r.Close()
+	vk := &vKeyboard{dev: newUinputDevice(&fileBackend{file: w}, keyboardCapabilities(newDeviceOptions(nil)))}
+
+	err = vk.KeyDown(KeyLeftctrl)
+	if err != nil {
+		t.Fatalf("Failed to send key down event: %v", err)
+	}
+	// destroying the device fails, since the pipe is not a uinput device
+	_ = vk.Close()
+
+	written, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("Failed to read events: %v", err)
+	}
+	expected := []inputEvent{
+		{Type: EvKey, Code: KeyLeftctrl, Value: btnStatePressed},
+		{Type: EvSyn, Code: SynReport},
+		{Type: EvKey, Code: KeyLeftctrl, Value: btnStateReleased},
+		{Type: EvSyn, Code: SynReport},
+	}
+	if actual := decodeEvents(t, written); !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, actual)
+	}
+}
+
+func TestReleaseAllWritesNothingIfNothingIsHeld(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create pipe: %v", err)
+	}
+	defer r.Close()
+	vm := vMouse{dev: newUinputDevice(&fileBackend{file: w}, mouseCapabilities(newDeviceOptions(nil)))}
+
+	err = vm.LeftClick()
+	if err != nil {
+		t.Fatalf("Failed to send click: %v", err)
+	}
+	err = vm.ReleaseAll()
+	if err != nil {
+		t.Fatalf("Failed to release all buttons: %v", err)
+	}
+	_ = w.Close()
+
+	written, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("Failed to read events: %v", err)
+	}
+	if n := len(decodeEvents(t, written)); n != 4 {
+		t.Fatalf("Expected only the events of the click to be written, but got %d events", n)
+	}
+}
+
+func releaseEvents(s *deviceState) []inputEvent {
+	var events []inputEvent
+	s.release(func(evType uint16, code uint16, value int32) {
+		s.track(evType, code, value)
+		events = append(events, inputEvent{Type: evType, Code: code, Value: value})
+	})
+	return events
+}
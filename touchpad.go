@@ -3,7 +3,6 @@ package uinput
 import (
 	"fmt"
 	"io"
-	"os"
 )
 
 // A TouchPad is an input device that uses absolute axis events, meaning that you can specify
@@ -42,18 +41,29 @@ type TouchPad interface {
 	// FetchSyspath will return the syspath to the device file.
 	FetchSyspath() (string, error)
 
+	// ReleaseAll will release all buttons that are held down. This is done automatically when the device is closed.
+	ReleaseAll() error
+
+	// Info will return information about the device as seen by the kernel, such as its syspath and event node.
+	Info() (DeviceInfo, error)
+
+	// WriteEvents will write the given events followed by a sync event (SYN_REPORT) as a single frame. This allows to
+	// send events that are not covered by the other methods. All events need to refer to codes that have been
+	// registered for the device, sync events are not allowed.
+	WriteEvents(events ...Event) error
+
 	io.Closer
 }
 
 type vTouchPad struct {
-	name       []byte
-	deviceFile *os.File
+	name []byte
+	dev  *uinputDevice
 }
 
 // CreateTouchPad will create a new touchpad device. note that you will need to define the x and y-axis boundaries
 // (min and max) within which the cursor maybe moved around.
-func CreateTouchPad(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32) (TouchPad, error) {
-	err := validateDevicePath(path)
+func CreateTouchPad(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32, opts ...Option) (TouchPad, error) {
+	err := validateDevicePath(path, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -62,131 +72,147 @@ func CreateTouchPad(path string, name []byte, minX int32, maxX int32, minY int32
 		return nil, err
 	}
 
-	fd, err := createTouchPad(path, name, minX, maxX, minY, maxY)
+	fd, err := createTouchPad(path, name, minX, maxX, minY, maxY, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	return vTouchPad{name: name, deviceFile: fd}, nil
+	return vTouchPad{name: name, dev: newUinputDevice(fd, touchPadCapabilities())}, nil
 }
 
 func (vTouch vTouchPad) MoveTo(x int32, y int32) error {
-	return sendAbsEvent(vTouch.deviceFile, x, y)
+	return sendAbsEvent(vTouch.dev, x, y)
 }
 
 func (vTouch vTouchPad) LeftClick() error {
-	err := sendBtnEvent(vTouch.deviceFile, []int{evMouseBtnLeft}, btnStatePressed)
+	err := sendBtnEvent(vTouch.dev, ButtonLeft, btnStatePressed)
 	if err != nil {
-		return fmt.Errorf("failed to issue the LeftClick event: %v", err)
+		return fmt.Errorf("failed to issue the LeftClick event: %w", err)
 	}
 
-	return sendBtnEvent(vTouch.deviceFile, []int{evMouseBtnLeft}, btnStateReleased)
+	return sendBtnEvent(vTouch.dev, ButtonLeft, btnStateReleased)
 }
 
 func (vTouch vTouchPad) RightClick() error {
-	err := sendBtnEvent(vTouch.deviceFile, []int{evMouseBtnRight}, btnStatePressed)
+	err := sendBtnEvent(vTouch.dev, ButtonRight, btnStatePressed)
 	if err != nil {
-		return fmt.Errorf("failed to issue the RightClick event: %v", err)
+		return fmt.Errorf("failed to issue the RightClick event: %w", err)
 	}
 
-	return sendBtnEvent(vTouch.deviceFile, []int{evMouseBtnRight}, btnStateReleased)
+	return sendBtnEvent(vTouch.dev, ButtonRight, btnStateReleased)
 }
 
 // LeftPress will simulate a press of the left mouse button. Note that the button will not be released until
 // LeftRelease is invoked.
 func (vTouch vTouchPad) LeftPress() error {
-	return sendBtnEvent(vTouch.deviceFile, []int{evMouseBtnLeft}, btnStatePressed)
+	return sendBtnEvent(vTouch.dev, ButtonLeft, btnStatePressed)
 }
 
 // LeftRelease will simulate the release of the left mouse button.
 func (vTouch vTouchPad) LeftRelease() error {
-	return sendBtnEvent(vTouch.deviceFile, []int{evMouseBtnLeft}, btnStateReleased)
+	return sendBtnEvent(vTouch.dev, ButtonLeft, btnStateReleased)
 }
 
 // RightPress will simulate the press of the right mouse button. Note that the button will not be released until
 // RightRelease is invoked.
 func (vTouch vTouchPad) RightPress() error {
-	return sendBtnEvent(vTouch.deviceFile, []int{evMouseBtnRight}, btnStatePressed)
+	return sendBtnEvent(vTouch.dev, ButtonRight, btnStatePressed)
 }
 
 // RightRelease will simulate the release of the right mouse button.
 func (vTouch vTouchPad) RightRelease() error {
-	return sendBtnEvent(vTouch.deviceFile, []int{evMouseBtnRight}, btnStateReleased)
+	return sendBtnEvent(vTouch.dev, ButtonRight, btnStateReleased)
 }
 
 func (vTouch vTouchPad) TouchDown() error {
-	return sendBtnEvent(vTouch.deviceFile, []int{evBtnTouch}, btnStatePressed)
+	return sendBtnEvent(vTouch.dev, ButtonTouch, btnStatePressed)
 }
 
 func (vTouch vTouchPad) TouchUp() error {
-	return sendBtnEvent(vTouch.deviceFile, []int{evBtnTouch}, btnStateReleased)
+	return sendBtnEvent(vTouch.dev, ButtonTouch, btnStateReleased)
+}
+
+// ReleaseAll will release everything that is currently held down, see the interface for details.
+func (vTouch vTouchPad) ReleaseAll() error {
+	return vTouch.dev.releaseAll()
+}
+
+// Info will return information about the device as seen by the kernel.
+func (vTouch vTouchPad) Info() (DeviceInfo, error) {
+	return vTouch.dev.info()
+}
+
+// WriteEvents will write the given events as a single frame.
+func (vTouch vTouchPad) WriteEvents(events ...Event) error {
+	return vTouch.dev.writeEvents(events)
 }
 
 func (vTouch vTouchPad) Close() error {
-	return closeDevice(vTouch.deviceFile)
+	return vTouch.dev.close()
+}
+
+var (
+	touchPadButtons = []int{ButtonLeft, ButtonRight, ButtonTouch}
+	touchPadAxes    = []int{AbsX, AbsY}
+)
+
+// touchPadCapabilities returns the codes registered by createTouchPad.
+func touchPadCapabilities() capabilities {
+	caps := make(capabilities)
+	caps.add(EvKey, touchPadButtons...)
+	caps.add(EvAbs, touchPadAxes...)
+	return caps
 }
 
-func createTouchPad(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32) (fd *os.File, err error) {
-	deviceFile, err := createDeviceFile(path)
+func createTouchPad(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32, opts []Option) (fd backend, err error) {
+	deviceFile, err := createDeviceFile(path, opts)
 	if err != nil {
-		return nil, fmt.Errorf("could not create absolute axis input device: %v", err)
+		return nil, fmt.Errorf("could not create absolute axis input device: %w", err)
 	}
 
-	err = registerDevice(deviceFile, uintptr(evKey))
+	err = registerDevice(deviceFile, uintptr(EvKey))
 	if err != nil {
-		_ = deviceFile.Close()
-		return nil, fmt.Errorf("failed to register key device: %v", err)
+		// registerDevice closes the device file on failure
+		return nil, fmt.Errorf("failed to register key device: %w", err)
 	}
 	// register button events (in order to enable left and right click)
-	for _, event := range []int{evMouseBtnLeft, evMouseBtnRight, evBtnTouch} {
+	for _, event := range touchPadButtons {
 		err = ioctl(deviceFile, uiSetKeyBit, uintptr(event))
 		if err != nil {
 			_ = deviceFile.Close()
-			return nil, fmt.Errorf("failed to register button event %v: %v", event, err)
+			return nil, fmt.Errorf("failed to register button event %v: %w", event, err)
 		}
 	}
 
-	err = registerDevice(deviceFile, uintptr(evAbs))
+	err = registerDevice(deviceFile, uintptr(EvAbs))
 	if err != nil {
-		_ = deviceFile.Close()
-		return nil, fmt.Errorf("failed to register absolute axis input device: %v", err)
+		// registerDevice closes the device file on failure
+		return nil, fmt.Errorf("failed to register absolute axis input device: %w", err)
 	}
 
 	// register x and y-axis events
-	for _, event := range []int{absX, absY} {
+	for _, event := range touchPadAxes {
 		err = ioctl(deviceFile, uiSetAbsBit, uintptr(event))
 		if err != nil {
 			_ = deviceFile.Close()
-			return nil, fmt.Errorf("failed to register absolute axis event %v: %v", event, err)
+			return nil, fmt.Errorf("failed to register absolute axis event %v: %w", event, err)
 		}
 	}
 
-	var absMin [absSize]int32
-	absMin[absX] = minX
-	absMin[absY] = minY
-
-	var absMax [absSize]int32
-	absMax[absX] = maxX
-	absMax[absY] = maxY
-
 	return createUsbDevice(deviceFile,
-		uinputUserDev{
-			Name: toUinputName(name),
-			ID: inputID{
+		deviceSetup{
+			name: name,
+			id: inputID{
 				Bustype: busUsb,
 				Vendor:  0x4711,
 				Product: 0x0817,
 				Version: 1},
-			Absmin: absMin,
-			Absmax: absMax})
+			absAxes: []absAxis{
+				{code: AbsX, min: minX, max: maxX},
+				{code: AbsY, min: minY, max: maxY}}}, opts)
 }
 
-func sendAbsEvent(deviceFile *os.File, xPos int32, yPos int32) error { // TODO: Perhaps move this to a more generic function? This conflicts with the gamepad ABS events which only have one value.
-	var ev [2]inputEvent
-	ev[0].Type = evAbs
-	ev[0].Code = absX
-	ev[0].Value = xPos
-
+func sendAbsEvent(dev *uinputDevice, xPos int32, yPos int32) error { // TODO: Perhaps move this to a more generic function? This conflicts with the gamepad ABS events which only have one value.
 	// Various tests (using evtest) have shown that positioning on x=0;y=0 doesn't trigger any event and will not move
 	// the cursor as expected. Setting at least one of the coordinates to -1 will however have the desired effect of
 	// moving the cursor to the upper left corner. Interestingly, the same is true for equivalent code in C, which rules
@@ -195,25 +221,15 @@ func sendAbsEvent(deviceFile *os.File, xPos int32, yPos int32) error { // TODO:
 		yPos--
 	}
 
-	ev[1].Type = evAbs
-	ev[1].Code = absY
-	ev[1].Value = yPos
-
-	for _, iev := range ev {
-		buf, err := inputEventToBuffer(iev)
-		if err != nil {
-			return fmt.Errorf("writing abs event failed: %v", err)
-		}
-
-		_, err = deviceFile.Write(buf)
-		if err != nil {
-			return fmt.Errorf("failed to write abs event to device file: %v", err)
-		}
+	err := dev.send(
+		inputEvent{Type: EvAbs, Code: AbsX, Value: xPos},
+		inputEvent{Type: EvAbs, Code: AbsY, Value: yPos})
+	if err != nil {
+		return fmt.Errorf("failed to write abs event to device file: %w", err)
 	}
-
-	return syncEvents(deviceFile)
+	return nil
 }
 
 func (vTouch vTouchPad) FetchSyspath() (string, error) {
-	return fetchSyspath(vTouch.deviceFile)
+	return vTouch.dev.fetchSyspath()
 }
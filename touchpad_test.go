@@ -1,6 +1,7 @@
 package uinput
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -99,8 +100,8 @@ func TestTouchPadCreationFailsOnEmptyPath(t *testing.T) {
 func TestTouchPadCreationFailsOnNonExistentPathName(t *testing.T) {
 	path := "/some/bogus/path"
 	_, err := CreateTouchPad(path, []byte("TouchDevice"), 0, 1024, 0, 768)
-	if !os.IsNotExist(err) {
-		t.Fatalf("Expected: os.IsNotExist error\nActual: %s", err)
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Expected: os.ErrNotExist error\nActual: %s", err)
 	}
 }
 
@@ -111,7 +112,7 @@ func TestTouchPadCreationFailsOnWrongPathName(t *testing.T) {
 	}
 	defer file.Close()
 
-	expected := "failed to register key device: failed to close device: inappropriate ioctl for device"
+	expected := "failed to register key device: invalid file handle returned from ioctl: inappropriate ioctl for device (failed to close device: inappropriate ioctl for device)"
 	_, err = CreateTouchPad(file.Name(), []byte("TouchDevice"), 0, 1024, 0, 768)
 	if err == nil || !(expected == err.Error()) {
 		t.Fatalf("Expected: %s\nActual: %s", expected, err)
@@ -71,26 +71,40 @@ device. Here are a few examples of how to use the virtual touch pad:
 
  3. Close the device
     Example: err = vt.Close()
+
+All devices are safe for concurrent use by multiple goroutines. The events of a single action (moving a stick along
+both of its axes, for example) are written to the device at once and never interleaved with the events of another
+action.
+
+Closing a device more than once is safe. Only the first call to Close reports whether the device could be destroyed,
+while all further calls return nil. Any other operation on a closed device returns ErrDeviceClosed.
 */
 package uinput
 
 import (
-	"bytes"
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"sync"
 	"syscall"
-	"time"
 	"unsafe"
 )
 
-func validateDevicePath(path string) error {
+func validateDevicePath(path string, opts []Option) error {
+	if newDeviceOptions(opts).fake != nil {
+		// the path is not used at all
+		return nil
+	}
 	if path == "" {
 		return errors.New("device path must not be empty")
 	}
 	_, err := os.Stat(path)
-	return err
+	if err != nil {
+		return accessError(err)
+	}
+	return nil
 }
 
 func validateUinputName(name []byte) error {
@@ -109,120 +123,383 @@ func toUinputName(name []byte) (uinputName [uinputMaxNameSize]byte) {
 	return fixedSizeName
 }
 
-func createDeviceFile(path string) (fd *os.File, err error) {
-	deviceFile, err := os.OpenFile(path, syscall.O_WRONLY|syscall.O_NONBLOCK, 0660)
+func createDeviceFile(path string, opts []Option) (fd backend, err error) {
+	if fake := newDeviceOptions(opts).fake; fake != nil {
+		return fake, nil
+	}
+	deviceFile, err := os.OpenFile(path, syscall.O_RDWR|syscall.O_NONBLOCK, 0660)
 	if err != nil {
-		return nil, errors.New("could not open device file")
+		return nil, accessError(fmt.Errorf("could not open device file: %w", err))
 	}
-	return deviceFile, err
+	return &fileBackend{file: deviceFile}, err
 }
 
-func registerDevice(deviceFile *os.File, evType uintptr) error {
+func registerDevice(deviceFile backend, evType uintptr) error {
 	err := ioctl(deviceFile, uiSetEvBit, evType)
 	if err != nil {
 		defer deviceFile.Close()
-		err = releaseDevice(deviceFile)
-		if err != nil {
-			return fmt.Errorf("failed to close device: %v", err)
+		releaseErr := releaseDevice(deviceFile)
+		if releaseErr != nil {
+			return fmt.Errorf("invalid file handle returned from ioctl: %w (failed to close device: %v)", err, releaseErr)
 		}
-		return fmt.Errorf("invalid file handle returned from ioctl: %v", err)
+		return fmt.Errorf("invalid file handle returned from ioctl: %w", err)
 	}
 	return nil
 }
 
-func createUsbDevice(deviceFile *os.File, dev uinputUserDev) (fd *os.File, err error) {
-	buf := new(bytes.Buffer)
-	err = binary.Write(buf, binary.LittleEndian, dev)
+// deviceSetup describes the identity and the absolute axes of a device that is about to be created.
+type deviceSetup struct {
+	name       []byte
+	id         inputID
+	effectsMax uint32
+	absAxes    []absAxis
+}
+
+// absAxis holds the range of a single absolute axis.
+type absAxis struct {
+	code       int
+	min        int32
+	max        int32
+	fuzz       int32
+	flat       int32
+	resolution int32
+}
+
+func createUsbDevice(deviceFile backend, setup deviceSetup, opts []Option) (fd backend, err error) {
+	options := newDeviceOptions(opts)
+	options.applyIdentity(&setup.id)
+
+	err = setPhysAndProperties(deviceFile, options)
 	if err != nil {
 		_ = deviceFile.Close()
-		return nil, fmt.Errorf("failed to write user device buffer: %v", err)
+		return nil, err
+	}
+
+	version, err := fetchUinputVersion(deviceFile)
+	if err == nil && version >= uinputSetupVersion {
+		err = setupDevice(deviceFile, setup)
+	} else {
+		err = writeUserDev(deviceFile, setup)
 	}
-	_, err = deviceFile.Write(buf.Bytes())
 	if err != nil {
 		_ = deviceFile.Close()
-		return nil, fmt.Errorf("failed to write uidev struct to device file: %v", err)
+		return nil, err
 	}
 
 	err = ioctl(deviceFile, uiDevCreate, uintptr(0))
 	if err != nil {
 		_ = deviceFile.Close()
-		return nil, fmt.Errorf("failed to create device: %v", err)
+		return nil, fmt.Errorf("failed to create device: %w", err)
+	}
+
+	ctx, cancel := options.readyContext()
+	defer cancel()
+	err = deviceFile.waitUntilReady(ctx)
+	if err != nil {
+		_ = closeDevice(deviceFile)
+		return nil, fmt.Errorf("device is not ready: %w", err)
+	}
+
+	return deviceFile, nil
+}
+
+// setPhysAndProperties sets the physical path and the input properties of the device, if any have been requested.
+func setPhysAndProperties(deviceFile backend, options deviceOptions) error {
+	if options.phys != "" {
+		phys := append([]byte(options.phys), 0)
+		err := ioctlPtr(deviceFile, uiSetPhys, unsafe.Pointer(&phys[0]))
+		if err != nil {
+			return fmt.Errorf("failed to set phys: %w", err)
+		}
+	}
+	for _, property := range options.properties {
+		if property < 0 || property > propMax {
+			return fmt.Errorf("input property %d is not in range (maximum is %d)", property, propMax)
+		}
+		err := ioctl(deviceFile, uiSetProp, uintptr(property))
+		if err != nil {
+			return fmt.Errorf("failed to set input property %d: %w", property, err)
+		}
+	}
+	return nil
+}
+
+// setupDevice configures the device using uiDevSetup and one uiAbsSetup call per absolute axis. Unlike the legacy
+// uinputUserDev struct, this allows to specify the resolution of each axis.
+func setupDevice(deviceFile backend, setup deviceSetup) error {
+	usetup := setup.uinputSetup()
+	err := ioctlPtr(deviceFile, uiDevSetup, unsafe.Pointer(&usetup))
+	if err != nil {
+		return fmt.Errorf("failed to set up device: %w", err)
+	}
+
+	for _, axis := range setup.absAxes {
+		absSetup := axis.uinputAbsSetup()
+		err = ioctlPtr(deviceFile, uiAbsSetup, unsafe.Pointer(&absSetup))
+		if err != nil {
+			return fmt.Errorf("failed to set up absolute axis %d: %w", axis.code, err)
+		}
 	}
+	return nil
+}
+
+// writeUserDev configures the device by writing the legacy uinputUserDev struct to the device file. This is the only
+// option on kernels that do not support uiDevSetup (prior to linux 4.5).
+func writeUserDev(deviceFile backend, setup deviceSetup) error {
+	dev := setup.uinputUserDev()
+	_, err := deviceFile.Write(dev.encode())
+	if err != nil {
+		return fmt.Errorf("failed to write uidev struct to device file: %w", err)
+	}
+	return nil
+}
+
+func (s deviceSetup) uinputSetup() uinputSetup {
+	return uinputSetup{
+		ID:         s.id,
+		Name:       toUinputName(s.name),
+		EffectsMax: s.effectsMax}
+}
+
+func (s deviceSetup) uinputUserDev() uinputUserDev {
+	dev := uinputUserDev{
+		Name:       toUinputName(s.name),
+		ID:         s.id,
+		EffectsMax: s.effectsMax}
+	for _, axis := range s.absAxes {
+		dev.Absmin[axis.code] = axis.min
+		dev.Absmax[axis.code] = axis.max
+		dev.Absfuzz[axis.code] = axis.fuzz
+		dev.Absflat[axis.code] = axis.flat
+	}
+	return dev
+}
+
+// encode serializes the struct in the same way binary.Write would, but without the use of reflection.
+func (dev *uinputUserDev) encode() []byte {
+	buf := make([]byte, uinputUserDevSize)
+	n := copy(buf, dev.Name[:])
+	for _, v := range []uint16{dev.ID.Bustype, dev.ID.Vendor, dev.ID.Product, dev.ID.Version} {
+		binary.LittleEndian.PutUint16(buf[n:], v)
+		n += 2
+	}
+	binary.LittleEndian.PutUint32(buf[n:], dev.EffectsMax)
+	n += 4
+	for _, values := range []*[absSize]int32{&dev.Absmax, &dev.Absmin, &dev.Absfuzz, &dev.Absflat} {
+		for _, v := range values {
+			binary.LittleEndian.PutUint32(buf[n:], uint32(v))
+			n += 4
+		}
+	}
+	return buf
+}
 
-	time.Sleep(time.Millisecond * 200)
+func (a absAxis) uinputAbsSetup() uinputAbsSetup {
+	return uinputAbsSetup{
+		Code: uint16(a.code),
+		AbsInfo: inputAbsInfo{
+			Minimum:    a.min,
+			Maximum:    a.max,
+			Fuzz:       a.fuzz,
+			Flat:       a.flat,
+			Resolution: a.resolution}}
+}
 
-	return deviceFile, err
+func fetchUinputVersion(deviceFile backend) (uint32, error) {
+	var version uint32
+	err := ioctlPtr(deviceFile, uiGetVersion, unsafe.Pointer(&version))
+	return version, err
 }
 
-func closeDevice(deviceFile *os.File) (err error) {
+// closeDevice destroys the device and closes the device file. The file is closed even if the device could not be
+// destroyed.
+func closeDevice(deviceFile backend) (err error) {
 	err = releaseDevice(deviceFile)
 	if err != nil {
-		return fmt.Errorf("failed to close device: %v", err)
+		_ = deviceFile.Close()
+		return fmt.Errorf("failed to close device: %w", err)
 	}
 	return deviceFile.Close()
 }
 
-func releaseDevice(deviceFile *os.File) (err error) {
+func releaseDevice(deviceFile backend) (err error) {
 	return ioctl(deviceFile, uiDevDestroy, uintptr(0))
 }
 
-func fetchSyspath(deviceFile *os.File) (string, error) {
-	sysInputDir := "/sys/devices/virtual/input/"
-	// 64 for name + 1 for null byte
-	path := make([]byte, 65)
-	err := ioctl(deviceFile, uiGetSysname, uintptr(unsafe.Pointer(&path[0])))
+// uinputDevice holds the device file of a created device along with a reusable frame, which allows to send events
+// without any allocations. It is shared by all copies of a device, so that the device may be used from multiple
+// goroutines.
+type uinputDevice struct {
+	file         backend
+	capabilities capabilities
+
+	// mutex guards the frame, the state and the lifecycle of the device. It makes sure that the events of one action
+	// are never interleaved with those of another action.
+	mutex  sync.Mutex
+	frame  frame
+	state  deviceState
+	closed bool
+}
 
-	sysInputDir = sysInputDir + string(path)
-	return sysInputDir, err
+func newUinputDevice(deviceFile backend, caps capabilities, restAxes ...uint16) *uinputDevice {
+	return &uinputDevice{file: deviceFile, capabilities: caps, state: deviceState{restAxes: restAxes}}
 }
 
-// Note that mice and touch pads do have buttons as well. Therefore, this function is used
-// by all currently available devices and resides in the main source file.
-func sendBtnEvent(deviceFile *os.File, keys []int, btnState int) (err error) {
-	for _, key := range keys {
-		buf, err := inputEventToBuffer(inputEvent{
-			Time:  syscall.Timeval{Sec: 0, Usec: 0},
-			Type:  evKey,
-			Code:  uint16(key),
-			Value: int32(btnState)})
-		if err != nil {
-			return fmt.Errorf("key event could not be set: %v", err)
-		}
-		_, err = deviceFile.Write(buf)
-		if err != nil {
-			return fmt.Errorf("writing btnEvent structure to the device file failed: %v", err)
+// send writes the given events followed by a sync event using a single write call.
+func (d *uinputDevice) send(events ...inputEvent) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.closed {
+		return ErrDeviceClosed
+	}
+	for _, ev := range events {
+		d.add(ev.Type, ev.Code, ev.Value)
+	}
+	return closedError(d.frame.writeTo(d.file))
+}
+
+// sendEvents writes the given events followed by a sync event using a single write call.
+func (d *uinputDevice) sendEvents(events []Event) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.closed {
+		return ErrDeviceClosed
+	}
+	for _, ev := range events {
+		d.add(ev.Type, ev.Code, ev.Value)
+	}
+	return closedError(d.frame.writeTo(d.file))
+}
+
+// writeEvents validates the given events against the capabilities of the device and writes them as a single frame.
+func (d *uinputDevice) writeEvents(events []Event) error {
+	for _, ev := range events {
+		if err := d.capabilities.assertRegistered(ev.Type, int(ev.Code)); err != nil {
+			return err
 		}
 	}
-	return syncEvents(deviceFile)
+	err := d.sendEvents(events)
+	if err != nil {
+		return fmt.Errorf("writing events to the device file failed: %w", err)
+	}
+	return nil
+}
+
+// add appends an event to the current frame and keeps track of the resulting state of the device.
+func (d *uinputDevice) add(evType uint16, code uint16, value int32) {
+	d.state.track(evType, code, value)
+	d.frame.add(evType, code, value)
+}
+
+// releaseAll releases all keys and buttons that are held down, re-centers all axes that have a rest position and
+// lifts all touch contacts using a single frame. Nothing is written if there is nothing to release.
+func (d *uinputDevice) releaseAll() error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.closed {
+		return ErrDeviceClosed
+	}
+	return d.release()
+}
+
+func (d *uinputDevice) release() error {
+	d.state.release(d.add)
+	if len(d.frame.buf) == 0 {
+		return nil
+	}
+	return closedError(d.frame.writeTo(d.file))
 }
 
-func syncEvents(deviceFile *os.File) (err error) {
-	buf, err := inputEventToBuffer(inputEvent{
-		Time:  syscall.Timeval{Sec: 0, Usec: 0},
-		Type:  evSyn,
-		Code:  uint16(synReport),
-		Value: 0})
+// close releases everything that is still held down and destroys the device. Only the first call has an effect and
+// reports whether the device could be destroyed, all further calls return nil.
+func (d *uinputDevice) close() error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.closed {
+		return nil
+	}
+	d.closed = true
+	// a failed release must not prevent the device from being destroyed
+	_ = d.release()
+	return closeDevice(d.file)
+}
+
+func (d *uinputDevice) fetchSyspath() (string, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.closed {
+		return "", ErrDeviceClosed
+	}
+	return fetchSyspath(d.file)
+}
+
+func (d *uinputDevice) info() (DeviceInfo, error) {
+	syspath, err := d.fetchSyspath()
+	if err != nil {
+		return DeviceInfo{}, fmt.Errorf("failed to fetch syspath: %w", err)
+	}
+	return readDeviceInfo(syspath, devInputDir)
+}
+
+// Note that mice and touch pads do have buttons as well. Therefore, this function is used
+// by all currently available devices and resides in the main source file.
+func sendBtnEvent(dev *uinputDevice, key int, btnState int) error {
+	err := dev.send(inputEvent{Type: EvKey, Code: uint16(key), Value: int32(btnState)})
 	if err != nil {
-		return fmt.Errorf("writing sync event failed: %v", err)
+		return fmt.Errorf("writing btnEvent structure to the device file failed: %w", err)
 	}
-	_, err = deviceFile.Write(buf)
-	return err
+	return nil
 }
 
-func inputEventToBuffer(iev inputEvent) (buffer []byte, err error) {
-	buf := bytes.NewBuffer(make([]byte, 0, 24))
-	err = binary.Write(buf, binary.LittleEndian, iev)
+// sendEvent writes a single event of the given type, followed by a sync event.
+func sendEvent(dev *uinputDevice, evType uint16, code uint16, value int32) error {
+	err := dev.send(inputEvent{Type: evType, Code: code, Value: value})
 	if err != nil {
-		return nil, fmt.Errorf("failed to write input event to buffer: %v", err)
+		return fmt.Errorf("writing event structure to the device file failed: %w", err)
 	}
-	return buf.Bytes(), nil
+	return nil
 }
 
-// original function taken from: https://github.com/tianon/debian-golang-pty/blob/master/ioctl.go
-func ioctl(deviceFile *os.File, cmd, ptr uintptr) error {
-	_, _, errorCode := syscall.Syscall(syscall.SYS_IOCTL, deviceFile.Fd(), cmd, ptr)
+// readEvents reads the events the kernel sends back to the device (force feedback requests, for example) and passes
+// them on to handle. It returns as soon as the device file is closed.
+func readEvents(deviceFile backend, handle func(inputEvent)) {
+	buf := make([]byte, inputEventSize)
+	for {
+		_, err := io.ReadFull(deviceFile, buf)
+		if err != nil {
+			return
+		}
+		handle(inputEventFromBuffer(buf))
+	}
+}
+
+// ioctl issues a request that takes an integer argument.
+func ioctl(deviceFile backend, cmd, ptr uintptr) error {
+	return deviceFile.ioctl(cmd, ptr)
+}
+
+// ioctlPtr is used for all requests that take a pointer to a struct or a buffer as argument.
+func ioctlPtr(deviceFile backend, cmd uintptr, ptr unsafe.Pointer) error {
+	return deviceFile.ioctlPtr(cmd, ptr)
+}
+
+// control issues a system call on the device file. Note that the raw connection is used, since calling Fd() would
+// put the device file into blocking mode, which in turn would prevent a pending read from being interrupted once
+// the device is closed. Failed requests are reported as IoctlError.
+func control(deviceFile *os.File, cmd uintptr, call func(fd uintptr) syscall.Errno) error {
+	conn, err := deviceFile.SyscallConn()
+	if err != nil {
+		return closedError(err)
+	}
+	var errorCode syscall.Errno
+	err = conn.Control(func(fd uintptr) {
+		errorCode = call(fd)
+	})
+	if err != nil {
+		return closedError(err)
+	}
 	if errorCode != 0 {
-		return errorCode
+		return &IoctlError{Request: cmd, Errno: errorCode}
 	}
 	return nil
 }
@@ -1,6 +1,9 @@
 package uinput
 
 import (
+	"bytes"
+	"encoding/binary"
+	"errors"
 	"os"
 	"strings"
 	"testing"
@@ -8,7 +11,7 @@ import (
 
 func TestValidateDevicePathEmptyPathPanics(t *testing.T) {
 	expected := "device path must not be empty"
-	err := validateDevicePath("")
+	err := validateDevicePath("", nil)
 	if err.Error() != expected {
 		t.Fatalf("Expected: %s\nActual: %s", expected, err)
 	}
@@ -16,9 +19,9 @@ func TestValidateDevicePathEmptyPathPanics(t *testing.T) {
 
 func TestValidateDevicePathInvalidPathPanics(t *testing.T) {
 	path := "/some/bogus/path"
-	err := validateDevicePath(path)
-	if !os.IsNotExist(err) {
-		t.Fatalf("Expected: os.IsNotExist error\nActual: %s", err)
+	err := validateDevicePath(path, nil)
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Expected: os.ErrNotExist error\nActual: %s", err)
 	}
 }
 
@@ -31,16 +34,19 @@ func TestValidateUinputNameEmptyNamePanics(t *testing.T) {
 }
 
 func TestFailedDeviceFileCreationGeneratesError(t *testing.T) {
-	expected := "could not open device file"
-	_, err := createDeviceFile("/root/testfile")
-	if err == nil || err.Error() != expected {
-		t.Fatalf("expected error, but got none")
+	expected := "could not open device file: "
+	_, err := createDeviceFile("/root/testfile", nil)
+	if err == nil || !strings.HasPrefix(err.Error(), expected) {
+		t.Fatalf("Expected: %s...\nActual: %v", expected, err)
+	}
+	if !errors.Is(err, ErrUinputMissing) || !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Expected error to match ErrUinputMissing and its original cause, but got: %v", err)
 	}
 }
 
 func TestNonExistentDeviceFileCausesError(t *testing.T) {
 	expected := "failed to write uidev struct to device file:"
-	_, err := createUsbDevice(nil, uinputUserDev{})
+	_, err := createUsbDevice(&fileBackend{}, deviceSetup{}, nil)
 	if err == nil {
 		t.Fatalf("expected error, but got none")
 	}
@@ -48,3 +54,127 @@ func TestNonExistentDeviceFileCausesError(t *testing.T) {
 		t.Fatalf("got '%v', but expected '%v'", err.Error(), expected)
 	}
 }
+
+func TestUinputSetupPayload(t *testing.T) {
+	setup := deviceSetup{
+		name:       []byte("Test"),
+		id:         inputID{Bustype: busUsb, Vendor: 0x4711, Product: 0x0815, Version: 1},
+		effectsMax: 16}
+
+	expected := []byte{0x03, 0x00, 0x11, 0x47, 0x15, 0x08, 0x01, 0x00, 'T', 'e', 's', 't'}
+	expected = append(expected, make([]byte, uinputMaxNameSize-4)...)
+	expected = append(expected, 0x10, 0x00, 0x00, 0x00)
+
+	actual := payload(t, setup.uinputSetup())
+	if !bytes.Equal(expected, actual) {
+		t.Fatalf("Expected: %x\nActual: %x", expected, actual)
+	}
+	if size := (uiDevSetup >> 16) & 0x3fff; len(actual) != size {
+		t.Fatalf("Expected payload size %d to match ioctl size %d", len(actual), size)
+	}
+}
+
+func TestUinputAbsSetupPayload(t *testing.T) {
+	axis := absAxis{code: AbsMtPositionX, min: -10, max: 1920, fuzz: 4, flat: 8, resolution: 12}
+
+	expected := []byte{
+		0x35, 0x00, 0x00, 0x00, // code + padding
+		0x00, 0x00, 0x00, 0x00, // value
+		0xf6, 0xff, 0xff, 0xff, // minimum
+		0x80, 0x07, 0x00, 0x00, // maximum
+		0x04, 0x00, 0x00, 0x00, // fuzz
+		0x08, 0x00, 0x00, 0x00, // flat
+		0x0c, 0x00, 0x00, 0x00, // resolution
+	}
+
+	actual := payload(t, axis.uinputAbsSetup())
+	if !bytes.Equal(expected, actual) {
+		t.Fatalf("Expected: %x\nActual: %x", expected, actual)
+	}
+	if size := (uiAbsSetup >> 16) & 0x3fff; len(actual) != size {
+		t.Fatalf("Expected payload size %d to match ioctl size %d", len(actual), size)
+	}
+}
+
+func TestLegacyUserDevCarriesAbsRanges(t *testing.T) {
+	setup := deviceSetup{
+		name: []byte("Test"),
+		absAxes: []absAxis{
+			{code: AbsX, min: 1, max: 1024, fuzz: 2, flat: 3, resolution: 12},
+			{code: AbsY, min: 4, max: 768}}}
+
+	dev := setup.uinputUserDev()
+	if dev.Absmin[AbsX] != 1 || dev.Absmax[AbsX] != 1024 || dev.Absfuzz[AbsX] != 2 || dev.Absflat[AbsX] != 3 {
+		t.Fatalf("Unexpected range for x axis: min %d, max %d, fuzz %d, flat %d",
+			dev.Absmin[AbsX], dev.Absmax[AbsX], dev.Absfuzz[AbsX], dev.Absflat[AbsX])
+	}
+	if dev.Absmin[AbsY] != 4 || dev.Absmax[AbsY] != 768 {
+		t.Fatalf("Unexpected range for y axis: min %d, max %d", dev.Absmin[AbsY], dev.Absmax[AbsY])
+	}
+	if len(payload(t, dev)) != 1116 {
+		t.Fatalf("Expected legacy payload to be 1116 bytes long")
+	}
+}
+
+func TestLegacyUserDevEncoding(t *testing.T) {
+	setup := deviceSetup{
+		name:       []byte("Test"),
+		id:         inputID{Bustype: busUsb, Vendor: 0x4711, Product: 0x0817, Version: 1},
+		effectsMax: 4,
+		absAxes:    []absAxis{{code: AbsX, min: -1, max: 1024, fuzz: 2, flat: 3}}}
+
+	dev := setup.uinputUserDev()
+	expected := payload(t, dev)
+	if actual := dev.encode(); !bytes.Equal(expected, actual) {
+		t.Fatalf("Expected: %x\nActual: %x", expected, actual)
+	}
+}
+
+func payload(t *testing.T, data interface{}) []byte {
+	buf := new(bytes.Buffer)
+	err := binary.Write(buf, binary.LittleEndian, data)
+	if err != nil {
+		t.Fatalf("Failed to encode payload: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestCloseIsIdempotent(t *testing.T) {
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to open %s: %v", os.DevNull, err)
+	}
+	vk := &vKeyboard{dev: newUinputDevice(&fileBackend{file: devNull}, keyboardCapabilities(newDeviceOptions(nil)))}
+
+	// /dev/null does not support UI_DEV_DESTROY, which has to be reported by the first call only
+	err = vk.Close()
+	var ioctlErr *IoctlError
+	if !errors.As(err, &ioctlErr) || ioctlErr.Request != uiDevDestroy {
+		t.Fatalf("Expected first close to report the failed destroy request, but got: %v", err)
+	}
+	err = vk.Close()
+	if err != nil {
+		t.Fatalf("Expected further calls to Close to succeed, but got: %v", err)
+	}
+	if _, err = devNull.Write([]byte{0}); !errors.Is(err, os.ErrClosed) {
+		t.Fatalf("Expected device file to be closed, even though the device could not be destroyed")
+	}
+}
+
+func TestOperationsOnClosedDeviceReturnErrDeviceClosed(t *testing.T) {
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to open %s: %v", os.DevNull, err)
+	}
+	vm := vMouse{dev: newUinputDevice(&fileBackend{file: devNull}, mouseCapabilities(newDeviceOptions(nil)))}
+	_ = vm.Close()
+
+	err = vm.LeftClick()
+	if !errors.Is(err, ErrDeviceClosed) {
+		t.Fatalf("Expected error to match ErrDeviceClosed, but got: %v", err)
+	}
+	_, err = vm.FetchSyspath()
+	if !errors.Is(err, ErrDeviceClosed) {
+		t.Fatalf("Expected error to match ErrDeviceClosed, but got: %v", err)
+	}
+}
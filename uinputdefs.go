@@ -1,6 +1,9 @@
 package uinput
 
-import "syscall"
+import (
+	"syscall"
+	"unsafe"
+)
 
 // types needed from uinput.h
 const (
@@ -8,6 +11,8 @@ const (
 	uiDevCreate       = 0x5501
 	uiDevDestroy      = 0x5502
 	uiDevSetup        = 0x405c5503
+	uiAbsSetup        = 0x401c5504
+	uiGetVersion      = 0x8004552d
 	// this is for 64 length buffer to store name
 	// for another length generate using : (len << 16) | 0x8000552C
 	uiGetSysname = 0x8041552c
@@ -16,47 +21,61 @@ const (
 
 	uiSetRelBit = 0x40045566
 	uiSetAbsBit = 0x40045567
+	uiSetMscBit = 0x40045568
+	uiSetLedBit = 0x40045569
+	uiSetSndBit = 0x4004556a
+	uiSetFFBit  = 0x4004556b
+	uiSetSwBit  = 0x4004556d
+	uiSetProp   = 0x4004556e
 	busUsb      = 0x03
+
+	// the phys string is passed as char pointer, which is why the request depends on the platform's pointer size
+	uiSetPhys = 0x40000000 | unsafe.Sizeof(uintptr(0))<<16 | 0x556c
+
+	// the size of the force feedback structs depends on the platform's pointer size
+	uiBeginFFUpload = 0xc0000000 | unsafe.Sizeof(uinputFFUpload{})<<16 | 0x55c8
+	uiEndFFUpload   = 0x40000000 | unsafe.Sizeof(uinputFFUpload{})<<16 | 0x55c9
+	uiBeginFFErase  = 0xc0000000 | unsafe.Sizeof(uinputFFErase{})<<16 | 0x55ca
+	uiEndFFErase    = 0x40000000 | unsafe.Sizeof(uinputFFErase{})<<16 | 0x55cb
+
+	evUinput   = 0x0101
+	uiFFUpload = 1
+	uiFFErase  = 2
+)
+
+// force feedback effects as specified in input-event-codes.h
+const (
+	ffRumble   = 0x50
+	ffPeriodic = 0x51
+	ffConstant = 0x52
+	ffSquare   = 0x58
+	ffTriangle = 0x59
+	ffSine     = 0x5a
+	ffSawUp    = 0x5b
+	ffSawDown  = 0x5c
+	ffGain     = 0x60
 )
 
-// input event codes as specified in input-event-codes.h
+// the highest code of each event type as specified in input-event-codes.h
 const (
-	evSyn     = 0x00
-	evKey     = 0x01
-	evRel     = 0x02
-	evAbs     = 0x03
-	relX      = 0x0
-	relY      = 0x1
-	relHWheel = 0x6
-	relWheel  = 0x8
-	relDial   = 0x7
-
-	absX     = 0x00
-	absY     = 0x01
-	absZ     = 0x02
-	absRX    = 0x03
-	absRY    = 0x04
-	absRZ    = 0x05
-	absHat0X = 0x10
-	absHat0Y = 0x11
-
-	absMtSlot       = 0x2f
-	absMtTouchMajor = 0x30
-	absMtPositionX  = 0x35
-	absMtPositionY  = 0x36
-	absMtTrackingId = 0x39
-
-	synReport        = 0
-	evMouseBtnLeft   = 0x110
-	evMouseBtnRight  = 0x111
-	evMouseBtnMiddle = 0x112
-	evBtnTouch       = 0x14a
+	keyCodeMax = 0x2ff
+	relMax     = 0x0f
+	absMax     = 0x3f
+	mscMax     = 0x07
+	swMax      = 0x10
+	ledMax     = 0x0f
+	sndMax     = 0x07
 )
 
 const (
 	btnStateReleased = 0
 	btnStatePressed  = 1
 	absSize          = 64
+
+	uinputUserDevSize = uinputMaxNameSize + 8 + 4 + 4*absSize*4
+
+	// uinputSetupVersion is the first uinput version (linux 4.5) that supports uiDevSetup and uiAbsSetup
+	uinputSetupVersion = 5
 )
 
 type inputID struct {
@@ -77,6 +96,71 @@ type uinputUserDev struct {
 	Absflat    [absSize]int32
 }
 
+// translated to go from uinput.h
+type uinputSetup struct {
+	ID         inputID
+	Name       [uinputMaxNameSize]byte
+	EffectsMax uint32
+}
+
+// translated to go from uinput.h
+type uinputAbsSetup struct {
+	Code    uint16
+	_       uint16
+	AbsInfo inputAbsInfo
+}
+
+// translated to go from input.h
+type inputAbsInfo struct {
+	Value      int32
+	Minimum    int32
+	Maximum    int32
+	Fuzz       int32
+	Flat       int32
+	Resolution int32
+}
+
+// translated to go from uinput.h
+type uinputFFUpload struct {
+	RequestID uint32
+	Retval    int32
+	Effect    ffEffect
+	Old       ffEffect
+}
+
+// translated to go from uinput.h
+type uinputFFErase struct {
+	RequestID uint32
+	Retval    int32
+	EffectID  uint32
+}
+
+// the largest member of the ff_effect union is ff_periodic_effect, which ends with a pointer to custom data
+const ffEffectUnionSize = 24 + unsafe.Sizeof(uintptr(0))
+
+// translated to go from input.h
+type ffEffect struct {
+	Type      uint16
+	ID        int16
+	Direction uint16
+	Trigger   ffTrigger
+	Replay    ffReplay
+	// the union is declared as an array of pointer sized words in order to get the alignment right
+	U [ffEffectUnionSize / unsafe.Sizeof(uintptr(0))]uintptr
+}
+
+// translated to go from input.h
+type ffTrigger struct {
+	Button   uint16
+	Interval uint16
+}
+
+// translated to go from input.h
+type ffReplay struct {
+	Length uint16
+	Delay  uint16
+}
+
 // translated to go from input.h
 type inputEvent struct {
 	Time  syscall.Timeval
@@ -0,0 +1,38 @@
+package uinput
+
+import "strconv"
+
+// A UnicodeInput enters characters that are not part of the keyboard layout, using an input method of the system
+// (see WithUnicodeInput). Since the keystrokes are interpreted by the software that receives them, the input method
+// needs to be supported by that software.
+type UnicodeInput interface {
+	// Keystrokes returns the keystrokes that enter the given character, typing all characters the input method
+	// requires using the given layout. The result is false if the character can not be entered.
+	Keystrokes(r rune, layout Layout) ([]Keystroke, bool)
+}
+
+// HexInput enters characters by their code point: Ctrl+Shift+U is followed by the hexadecimal code point and a space,
+// for example Ctrl+Shift+U 2 6 0 3 Space for ☃. This is supported by GTK applications and by IBus. Like the digits, the
+// U is typed using the key that produces it on the layout.
+var HexInput UnicodeInput = hexInput{}
+
+type hexInput struct{}
+
+func (hexInput) Keystrokes(r rune, layout Layout) ([]Keystroke, bool) {
+	u, ok := layout.Keystrokes('u')
+	if !ok || len(u) != 1 {
+		return nil, false
+	}
+	trigger := u[0]
+	trigger.Modifiers |= ModCtrl | ModShift
+
+	strokes := []Keystroke{trigger}
+	for _, digit := range strconv.FormatInt(int64(r), 16) + " " {
+		s, ok := layout.Keystrokes(digit)
+		if !ok {
+			return nil, false
+		}
+		strokes = append(strokes, s...)
+	}
+	return strokes, true
+}
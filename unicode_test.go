@@ -0,0 +1,119 @@
+package uinput
+
+import (
+	"reflect"
+	"testing"
+)
+
+// keyEvents returns the events of the given key presses and releases, each of which is followed by a sync event.
+func keyEvents(keys ...int32) []Event {
+	var events []Event
+	for _, key := range keys {
+		value := int32(1)
+		if key < 0 {
+			key, value = -key, 0
+		}
+		events = append(events, Event{Type: EvKey, Code: uint16(key), Value: value}, Event{Type: EvSyn, Code: SynReport})
+	}
+	return events
+}
+
+// layoutDvorak is the US Dvorak layout, which moves the letters used by HexInput to other keys.
+var layoutDvorak = newKeymap("us(dvorak)", layoutRows(
+	[]string{"`~", "1!", "2@", "3#", "4$", "5%", "6^", "7&", "8*", "9(", "0)", "[{", "]}"},
+	[]string{"'\"", ",<", ".>", "pP", "yY", "fF", "gG", "cC", "rR", "lL", "/?", "=+"},
+	[]string{"aA", "oO", "eE", "uU", "iI", "dD", "hH", "tT", "nN", "sS", "-_", "\\|"},
+	[]string{"", ";:", "qQ", "jJ", "kK", "xX", "bB", "mM", "wW", "vV", "zZ"},
+))
+
+func TestHexInputKeystrokes(t *testing.T) {
+	tests := []struct {
+		layout   Layout
+		char     rune
+		expected []Keystroke
+	}{
+		{LayoutUS, '☃', []Keystroke{
+			{Key: KeyU, Modifiers: ModCtrl | ModShift},
+			{Key: Key2}, {Key: Key6}, {Key: Key0}, {Key: Key3}, {Key: KeySpace},
+		}},
+		{LayoutFrench, '😀', []Keystroke{
+			{Key: KeyU, Modifiers: ModCtrl | ModShift},
+			{Key: Key1, Modifiers: ModShift}, {Key: KeyF}, {Key: Key6, Modifiers: ModShift}, {Key: Key0, Modifiers: ModShift},
+			{Key: Key0, Modifiers: ModShift}, {Key: KeySpace},
+		}},
+		// the U is typed using the key that produces it on the layout
+		{layoutDvorak, 'é', []Keystroke{
+			{Key: KeyF, Modifiers: ModCtrl | ModShift},
+			{Key: KeyD}, {Key: Key9}, {Key: KeySpace},
+		}},
+	}
+
+	for _, test := range tests {
+		actual, ok := HexInput.Keystrokes(test.char, test.layout)
+		if !ok || !reflect.DeepEqual(test.expected, actual) {
+			t.Fatalf("Layout %q, character %q\nExpected: %+v\nActual: %+v", test.layout.Name(), test.char, test.expected, actual)
+		}
+	}
+}
+
+func TestHexInputRequiresU(t *testing.T) {
+	layout := newKeymap("digits", layoutRows([]string{"", "1", "2", "3", "4", "5", "6", "7", "8", "9", "0"}, nil, nil, nil))
+	if strokes, ok := HexInput.Keystrokes('☃', layout); ok {
+		t.Fatalf("Expected layouts without u to be rejected, but got %+v", strokes)
+	}
+}
+
+func TestKeyboardTypesUnsupportedCharactersUsingHexInput(t *testing.T) {
+	fake := NewFakeBackend()
+	vk, err := CreateKeyboard("/dev/uinput", []byte("Test Keyboard"), WithFakeBackend(fake), WithUnicodeInput(HexInput))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	defer vk.Close()
+
+	err = vk.Type("a€")
+	if err != nil {
+		t.Fatalf("Failed to type text. Last error was: %s\n", err)
+	}
+
+	expected := keyEvents(
+		KeyA, -KeyA,
+		KeyLeftctrl, KeyLeftshift, KeyU, -KeyU, -KeyLeftshift, -KeyLeftctrl,
+		Key2, -Key2, Key0, -Key0, KeyA, -KeyA, KeyC, -KeyC, KeySpace, -KeySpace)
+	if actual := fake.Events(); !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, actual)
+	}
+}
+
+// composeInput is a UnicodeInput that only supports a single character.
+type composeInput struct {
+	char    rune
+	strokes []Keystroke
+}
+
+func (c composeInput) Keystrokes(r rune, layout Layout) ([]Keystroke, bool) {
+	return c.strokes, r == c.char
+}
+
+func TestKeyboardTriesUnicodeInputsInOrder(t *testing.T) {
+	fake := NewFakeBackend()
+	compose := composeInput{char: 'é', strokes: []Keystroke{{Key: KeyCompose}, {Key: KeyApostrophe}, {Key: KeyE}}}
+	vk, err := CreateKeyboard("/dev/uinput", []byte("Test Keyboard"), WithFakeBackend(fake), WithUnicodeInput(compose, HexInput))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	defer vk.Close()
+
+	err = vk.Type("éè")
+	if err != nil {
+		t.Fatalf("Failed to type text. Last error was: %s\n", err)
+	}
+
+	expected := keyEvents(
+		KeyCompose, -KeyCompose, KeyApostrophe, -KeyApostrophe, KeyE, -KeyE,
+		KeyLeftctrl, KeyLeftshift, KeyU, -KeyU, -KeyLeftshift, -KeyLeftctrl,
+		KeyE, -KeyE, Key8, -Key8, KeySpace, -KeySpace)
+	if actual := fake.Events(); !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, actual)
+	}
+}
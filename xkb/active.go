@@ -0,0 +1,81 @@
+package xkb
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ErrNoActiveLayout is returned by ActiveLayout if none of the configuration files sets a keyboard layout.
+var ErrNoActiveLayout = errors.New("no keyboard layout configured")
+
+// configFiles are the files that configure the keyboard layout of the system: /etc/default/keyboard is used by Debian
+// based distributions, /etc/vconsole.conf by systemd (localectl).
+var configFiles = []string{"/etc/default/keyboard", "/etc/vconsole.conf"}
+
+// ActiveLayout returns the layout and variant that are configured for the system, as set by XKBLAYOUT and XKBVARIANT
+// in /etc/default/keyboard or /etc/vconsole.conf. If more than one layout is configured, the first one is returned.
+// Note that desktop environments may override the layout of the system for their session.
+func ActiveLayout() (layout, variant string, err error) {
+	return readActiveLayout(configFiles)
+}
+
+// LoadActiveLayout loads the layout that is configured for the system from DefaultDir (see ActiveLayout).
+func LoadActiveLayout() (*Layout, error) {
+	layout, variant, err := ActiveLayout()
+	if err != nil {
+		return nil, err
+	}
+	return LoadLayout(DefaultDir, layout, variant)
+}
+
+// readActiveLayout returns the layout and variant of the first of the given files that sets a layout. Files that do
+// not exist are skipped.
+func readActiveLayout(paths []string) (layout, variant string, err error) {
+	for _, path := range paths {
+		settings, err := readShellVariables(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", "", fmt.Errorf("failed to read keyboard configuration: %w", err)
+		}
+
+		layouts := strings.Split(settings["XKBLAYOUT"], ",")
+		if strings.TrimSpace(layouts[0]) == "" {
+			continue
+		}
+		// the variants are listed in the same order as the layouts
+		variants := strings.Split(settings["XKBVARIANT"], ",")
+		return strings.TrimSpace(layouts[0]), strings.TrimSpace(variants[0]), nil
+	}
+	return "", "", ErrNoActiveLayout
+}
+
+// readShellVariables reads the assignments of a file in shell syntax, like XKBLAYOUT="de". Comments and all other
+// lines are skipped.
+func readShellVariables(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	variables := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		i := strings.IndexByte(line, '=')
+		if strings.HasPrefix(line, "#") || i <= 0 {
+			continue
+		}
+		value := strings.TrimSpace(line[i+1:])
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		variables[strings.TrimSpace(line[:i])] = value
+	}
+	return variables, scanner.Err()
+}
@@ -0,0 +1,34 @@
+package xkb
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestReadActiveLayout(t *testing.T) {
+	tests := []struct {
+		paths   []string
+		layout  string
+		variant string
+	}{
+		{[]string{"testdata/keyboard", "testdata/vconsole.conf"}, "de", "nodeadkeys"},
+		{[]string{"testdata/missing", "testdata/vconsole.conf"}, "fr", ""},
+	}
+
+	for _, test := range tests {
+		layout, variant, err := readActiveLayout(test.paths)
+		if err != nil {
+			t.Fatalf("Failed to read the active layout from %v: %v", test.paths, err)
+		}
+		if layout != test.layout || variant != test.variant {
+			t.Fatalf("Expected %s(%s), but got %s(%s)", test.layout, test.variant, layout, variant)
+		}
+	}
+}
+
+func TestReadActiveLayoutFailsWithoutConfiguration(t *testing.T) {
+	_, _, err := readActiveLayout([]string{"testdata/missing", "testdata/symbols/us"})
+	if !errors.Is(err, ErrNoActiveLayout) {
+		t.Fatalf("Expected ErrNoActiveLayout, but got: %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+package xkb
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bendahl/uinput"
+)
+
+// DefaultComposeFile is the Compose file of the en_US.UTF-8 locale, which is included by the Compose files of most
+// other locales.
+const DefaultComposeFile = "/usr/share/X11/locale/en_US.UTF-8/Compose"
+
+// composeSequence matches lines like <Multi_key> <apostrophe> <e> : "é" eacute
+var composeSequence = regexp.MustCompile(`^((?:<[^>]+>\s*)+):\s*"((?:[^"\\]|\\.)*)"`)
+
+// Compose enters characters using the compose sequences of a Compose file, for example Compose ' e for é. It
+// implements uinput.UnicodeInput, so that it may be passed to uinput.WithUnicodeInput.
+//
+// Only sequences that start with the compose key (Multi_key) are used, which requires a compose key to be configured
+// for the system, for example using the xkb option compose:ralt. Sequences that require dead keys are ignored.
+type Compose struct {
+	key       int
+	sequences map[rune][][]rune
+}
+
+// LoadCompose reads the compose sequences from the given Compose file, for example DefaultComposeFile. The compose
+// key is the key code of the key that acts as compose key, for example uinput.KeyRightalt for compose:ralt.
+func LoadCompose(path string, composeKey int) (*Compose, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open compose file: %w", err)
+	}
+	defer file.Close()
+	return ReadCompose(file, composeKey)
+}
+
+// ReadCompose reads the compose sequences of a Compose file (see LoadCompose). Include statements are ignored.
+func ReadCompose(r io.Reader, composeKey int) (*Compose, error) {
+	c := &Compose{key: composeKey, sequences: make(map[rune][][]rune)}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") || strings.HasPrefix(text, "include") {
+			continue
+		}
+		m := composeSequence.FindStringSubmatch(text)
+		if m == nil {
+			return nil, fmt.Errorf("invalid compose sequence in line %d: %q", line, text)
+		}
+
+		result := []rune(unescape(m[2]))
+		keysyms := strings.Fields(strings.NewReplacer("<", " ", ">", " ").Replace(m[1]))
+		if len(result) != 1 || keysyms[0] != "Multi_key" {
+			continue
+		}
+		sequence, ok := keysymRunes(keysyms[1:])
+		if ok {
+			c.sequences[result[0]] = append(c.sequences[result[0]], sequence)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read compose file: %w", err)
+	}
+
+	// prefer short sequences, while keeping the order of the file otherwise
+	for _, sequences := range c.sequences {
+		sort.SliceStable(sequences, func(i, j int) bool { return len(sequences[i]) < len(sequences[j]) })
+	}
+	return c, nil
+}
+
+// unescape resolves the backslash escapes of a quoted string.
+func unescape(s string) string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range s {
+		if r == '\\' && !escaped {
+			escaped = true
+			continue
+		}
+		escaped = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// keysymRunes returns the characters of the given keysyms. The result is false if any of the keysyms does not produce
+// a character.
+func keysymRunes(keysyms []string) ([]rune, bool) {
+	runes := make([]rune, len(keysyms))
+	for i, keysym := range keysyms {
+		r, ok := keysymRune(keysym)
+		if !ok {
+			return nil, false
+		}
+		runes[i] = r
+	}
+	return runes, true
+}
+
+// Keystrokes returns the keystrokes that enter the given character: the compose key, followed by the characters of the
+// shortest compose sequence that can be typed using the given layout.
+func (c *Compose) Keystrokes(r rune, layout uinput.Layout) ([]uinput.Keystroke, bool) {
+	for _, sequence := range c.sequences[r] {
+		strokes := []uinput.Keystroke{{Key: c.key}}
+		for _, char := range sequence {
+			s, ok := layout.Keystrokes(char)
+			if !ok {
+				strokes = nil
+				break
+			}
+			strokes = append(strokes, s...)
+		}
+		if strokes != nil {
+			return strokes, true
+		}
+	}
+	return nil, false
+}
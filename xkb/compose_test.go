@@ -0,0 +1,95 @@
+package xkb
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bendahl/uinput"
+)
+
+func loadTestCompose(t *testing.T) *Compose {
+	compose, err := LoadCompose("testdata/Compose", uinput.KeyCompose)
+	if err != nil {
+		t.Fatalf("Failed to load compose file: %v", err)
+	}
+	return compose
+}
+
+func TestComposeKeystrokes(t *testing.T) {
+	compose := loadTestCompose(t)
+	key := func(key int) uinput.Keystroke { return uinput.Keystroke{Key: key} }
+	shift := func(key int) uinput.Keystroke { return uinput.Keystroke{Key: key, Modifiers: uinput.ModShift} }
+
+	tests := []struct {
+		layout   uinput.Layout
+		char     rune
+		expected []uinput.Keystroke
+	}{
+		{uinput.LayoutUS, '\\', []uinput.Keystroke{key(uinput.KeyCompose), key(uinput.KeySlash), key(uinput.KeySlash)}},
+		{uinput.LayoutUS, '¨', []uinput.Keystroke{key(uinput.KeyCompose), shift(uinput.KeyApostrophe), shift(uinput.KeyApostrophe)}},
+		// the acute accent is not part of the US layout
+		{uinput.LayoutUS, 'é', []uinput.Keystroke{key(uinput.KeyCompose), key(uinput.KeyApostrophe), key(uinput.KeyE)}},
+		{uinput.LayoutGerman, 'é', []uinput.Keystroke{key(uinput.KeyCompose), key(uinput.KeyEqual), key(uinput.KeyEqual), key(uinput.KeyE)}},
+		{uinput.LayoutGerman, '♥', []uinput.Keystroke{key(uinput.KeyCompose), key(uinput.Key102Nd), key(uinput.Key3)}},
+	}
+
+	for _, test := range tests {
+		actual, ok := compose.Keystrokes(test.char, test.layout)
+		if !ok || !reflect.DeepEqual(test.expected, actual) {
+			t.Fatalf("Layout %q, character %q\nExpected: %+v\nActual: %+v", test.layout.Name(), test.char, test.expected, actual)
+		}
+	}
+}
+
+func TestComposeIgnoresDeadKeySequences(t *testing.T) {
+	compose := loadTestCompose(t)
+	if strokes, ok := compose.Keystrokes('"', uinput.LayoutUS); ok {
+		t.Fatalf("Expected sequences without the compose key to be ignored, but got %+v", strokes)
+	}
+}
+
+func TestReadComposeRejectsInvalidSequences(t *testing.T) {
+	for _, src := range []string{
+		"<Multi_key> <s> <s> ß\n",
+		"<Multi_key> <s> <s> : \"ß\n",
+		": \"ß\"\n",
+	} {
+		_, err := ReadCompose(strings.NewReader(src), uinput.KeyCompose)
+		if err == nil {
+			t.Fatalf("Expected %q to be rejected", src)
+		}
+	}
+}
+
+func TestKeyboardTypesUsingComposeSequences(t *testing.T) {
+	fake := uinput.NewFakeBackend()
+	vk, err := uinput.CreateKeyboard("/dev/uinput", []byte("Test Keyboard"),
+		uinput.WithFakeBackend(fake), uinput.WithUnicodeInput(loadTestCompose(t), uinput.HexInput))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	defer vk.Close()
+
+	err = vk.Type("ß☃")
+	if err != nil {
+		t.Fatalf("Failed to type text. Last error was: %s\n", err)
+	}
+
+	var expected []uinput.Event
+	for _, key := range []struct {
+		code  uint16
+		value int32
+	}{
+		{uinput.KeyCompose, 1}, {uinput.KeyCompose, 0}, {uinput.KeyS, 1}, {uinput.KeyS, 0}, {uinput.KeyS, 1}, {uinput.KeyS, 0},
+		{uinput.KeyLeftctrl, 1}, {uinput.KeyLeftshift, 1}, {uinput.KeyU, 1}, {uinput.KeyU, 0}, {uinput.KeyLeftshift, 0}, {uinput.KeyLeftctrl, 0},
+		{uinput.Key2, 1}, {uinput.Key2, 0}, {uinput.Key6, 1}, {uinput.Key6, 0}, {uinput.Key0, 1}, {uinput.Key0, 0},
+		{uinput.Key3, 1}, {uinput.Key3, 0}, {uinput.KeySpace, 1}, {uinput.KeySpace, 0},
+	} {
+		expected = append(expected,
+			uinput.Event{Type: uinput.EvKey, Code: key.code, Value: key.value}, uinput.Event{Type: uinput.EvSyn, Code: uinput.SynReport})
+	}
+	if actual := fake.Events(); !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, actual)
+	}
+}
@@ -0,0 +1,67 @@
+//go:build ignore
+// +build ignore
+
+// gen_keysyms generates keysyms.go from keysymdef.h, which is part of the X11 protocol headers (x11proto-dev on
+// Debian based distributions). Only keysyms that map to a Unicode character are included.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"os"
+	"regexp"
+	"strconv"
+)
+
+// matches lines like "#define XK_adiaeresis 0x00e4  /* U+00E4 LATIN SMALL LETTER A WITH DIAERESIS */", but not
+// deprecated names, which have their code point in parentheses
+var keysymDef = regexp.MustCompile(`^#define XK_([a-zA-Z_0-9]+)\s+0x[0-9a-fA-F]+\s*/\* U\+([0-9A-F]{4,6}) `)
+
+func main() {
+	path := "/usr/include/X11/keysymdef.h"
+	if len(os.Args) > 1 {
+		path = os.Args[1]
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by gen_keysyms.go from keysymdef.h; DO NOT EDIT.\n\n")
+	buf.WriteString("package xkb\n\n")
+	buf.WriteString("// keysyms maps the names of keysyms to the characters they produce.\n")
+	buf.WriteString("var keysyms = map[string]rune{\n")
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		m := keysymDef.FindStringSubmatch(scanner.Text())
+		if m == nil || seen[m[1]] {
+			continue
+		}
+		seen[m[1]] = true
+		r, err := strconv.ParseUint(m[2], 16, 32)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Fprintf(&buf, "\t%q: 0x%04x,\n", m[1], r)
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatal(err)
+	}
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = ioutil.WriteFile("keysyms.go", src, 0644)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,205 @@
+/*
+Package xkb reads keyboard layouts from the xkb symbols files of the X Keyboard Extension, which are used by X11 and
+Wayland compositors alike. This allows to type text using layouts that are not built into the uinput package:
+
+	layout, err := xkb.LoadLayout(xkb.DefaultDir, "de", "")
+	if err != nil {
+		return err
+	}
+	keyboard, err := uinput.CreateKeyboard("/dev/uinput", []byte("testkeyboard"), uinput.WithLayout(layout))
+
+The layout that has been configured for the system can be determined using ActiveLayout, or loaded right away using
+LoadActiveLayout.
+
+Only the first group of a layout is used, and only the first four shift levels are supported, which are selected by
+Shift and AltGr (the right alt key). Accented letters are typed using the dead keys for grave, acute, circumflex, tilde
+and diaeresis, by pressing the dead key followed by the letter, and the accent itself by pressing the dead key twice.
+Characters that require other dead keys are not part of a layout. Such characters may be entered using compose
+sequences instead, which are read from Compose files using LoadCompose.
+*/
+package xkb
+
+//go:generate go run gen_keysyms.go
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/bendahl/uinput"
+)
+
+// DefaultDir is the directory that holds the symbols files on most distributions.
+const DefaultDir = "/usr/share/X11/xkb/symbols"
+
+// keyCodes maps the names that symbols files use for the keys of the alphanumeric block to their key codes.
+var keyCodes = map[string]int{
+	"TLDE": uinput.KeyGrave, "AE01": uinput.Key1, "AE02": uinput.Key2, "AE03": uinput.Key3, "AE04": uinput.Key4,
+	"AE05": uinput.Key5, "AE06": uinput.Key6, "AE07": uinput.Key7, "AE08": uinput.Key8, "AE09": uinput.Key9,
+	"AE10": uinput.Key0, "AE11": uinput.KeyMinus, "AE12": uinput.KeyEqual, "AE13": uinput.KeyYen,
+
+	"AD01": uinput.KeyQ, "AD02": uinput.KeyW, "AD03": uinput.KeyE, "AD04": uinput.KeyR, "AD05": uinput.KeyT,
+	"AD06": uinput.KeyY, "AD07": uinput.KeyU, "AD08": uinput.KeyI, "AD09": uinput.KeyO, "AD10": uinput.KeyP,
+	"AD11": uinput.KeyLeftbrace, "AD12": uinput.KeyRightbrace,
+
+	"AC01": uinput.KeyA, "AC02": uinput.KeyS, "AC03": uinput.KeyD, "AC04": uinput.KeyF, "AC05": uinput.KeyG,
+	"AC06": uinput.KeyH, "AC07": uinput.KeyJ, "AC08": uinput.KeyK, "AC09": uinput.KeyL, "AC10": uinput.KeySemicolon,
+	"AC11": uinput.KeyApostrophe, "AC12": uinput.KeyBackslash, "BKSL": uinput.KeyBackslash,
+
+	"LSGT": uinput.Key102Nd, "AB01": uinput.KeyZ, "AB02": uinput.KeyX, "AB03": uinput.KeyC, "AB04": uinput.KeyV,
+	"AB05": uinput.KeyB, "AB06": uinput.KeyN, "AB07": uinput.KeyM, "AB08": uinput.KeyComma, "AB09": uinput.KeyDot,
+	"AB10": uinput.KeySlash, "AB11": uinput.KeyRo,
+}
+
+// levelModifiers are the modifiers that select each of the supported shift levels.
+var levelModifiers = []uinput.Modifier{0, uinput.ModShift, uinput.ModAltGr, uinput.ModShift | uinput.ModAltGr}
+
+// deadKeys maps the keysyms of the supported dead keys to the keysyms of their spacing accents. The letters a dead key
+// composes are found by keysym name: dead_acute followed by e produces eacute.
+var deadKeys = map[string]string{
+	"dead_grave":      "grave",
+	"dead_acute":      "acute",
+	"dead_circumflex": "asciicircum",
+	"dead_tilde":      "asciitilde",
+	"dead_diaeresis":  "diaeresis",
+}
+
+// deadKeyBases are the characters that may follow a dead key.
+const deadKeyBases = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// Layout is a keyboard layout that has been read from symbols files. It implements uinput.Layout, so that it may be
+// passed to uinput.WithLayout.
+type Layout struct {
+	name    string
+	strokes map[rune][]uinput.Keystroke
+}
+
+// LoadLayout reads a layout from the symbols files in dir, for example LoadLayout(DefaultDir, "de", "nodeadkeys").
+// The default variant of the layout is used if variant is empty.
+func LoadLayout(dir, layout, variant string) (*Layout, error) {
+	keys, err := newSymbolsLoader(dir).load(layout, variant, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load layout %s: %w", layoutName(layout, variant), err)
+	}
+	return newLayout(layoutName(layout, variant), keys), nil
+}
+
+func layoutName(layout, variant string) string {
+	if variant == "" {
+		return layout
+	}
+	return layout + "(" + variant + ")"
+}
+
+// newLayout maps the characters of the given keys to keystrokes. If a character is produced by more than one key, the
+// keystroke that requires the least modifiers is used. Characters that are composed using dead keys are only used if
+// they can not be typed directly.
+func newLayout(name string, keys keyMap) *Layout {
+	l := &Layout{name: name, strokes: map[rune][]uinput.Keystroke{
+		' ':  {{Key: uinput.KeySpace}},
+		'\t': {{Key: uinput.KeyTab}},
+		'\n': {{Key: uinput.KeyEnter}},
+	}}
+
+	// sort the keys, so that the result does not depend on the order of the map
+	var names []string
+	for name := range keys {
+		if _, ok := keyCodes[name]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool { return keyCodes[names[i]] < keyCodes[names[j]] })
+
+	type deadKey struct {
+		stroke uinput.Keystroke
+		keysym string
+	}
+	var dead []deadKey
+	for level, modifiers := range levelModifiers {
+		for _, name := range names {
+			if level >= len(keys[name]) {
+				continue
+			}
+			keysym := keys[name][level]
+			stroke := uinput.Keystroke{Key: keyCodes[name], Modifiers: modifiers}
+			if _, ok := deadKeys[keysym]; ok {
+				dead = append(dead, deadKey{stroke: stroke, keysym: keysym})
+				continue
+			}
+			if r, ok := keysymRune(keysym); ok {
+				l.add(r, stroke)
+			}
+		}
+	}
+
+	for _, d := range dead {
+		l.add(keysyms[deadKeys[d.keysym]], d.stroke, d.stroke)
+		accent := strings.TrimPrefix(d.keysym, "dead_")
+		for _, base := range deadKeyBases {
+			composed, ok := keysyms[string(base)+accent]
+			if strokes := l.strokes[base]; ok && len(strokes) == 1 {
+				l.add(composed, d.stroke, strokes[0])
+			}
+		}
+	}
+	return l
+}
+
+// add registers the keystrokes of a character, unless the character can already be typed.
+func (l *Layout) add(r rune, strokes ...uinput.Keystroke) {
+	if _, ok := l.strokes[r]; !ok {
+		l.strokes[r] = strokes
+	}
+}
+
+// keysymRune returns the character a keysym produces. Keysyms may be given by name (adiaeresis), by Unicode code
+// point (U00E4) or by number (0x10000e4).
+func keysymRune(keysym string) (rune, bool) {
+	if r, ok := keysyms[keysym]; ok {
+		return r, true
+	}
+	if len(keysym) > 1 && keysym[0] == 'U' {
+		r, err := strconv.ParseUint(keysym[1:], 16, 32)
+		return rune(r), err == nil && r <= unicode.MaxRune
+	}
+	if strings.HasPrefix(keysym, "0x") {
+		v, err := strconv.ParseUint(keysym[2:], 16, 32)
+		switch {
+		case err != nil:
+			return 0, false
+		case v >= 0x01000000 && v <= 0x01000000+unicode.MaxRune:
+			// keysyms for Unicode characters
+			return rune(v - 0x01000000), true
+		case v >= 0x20 && v <= 0x7e || v >= 0xa0 && v <= 0xff:
+			// Latin-1 keysyms match their code points
+			return rune(v), true
+		}
+	}
+	return 0, false
+}
+
+// Name returns the name of the layout, for example "de(nodeadkeys)".
+func (l *Layout) Name() string {
+	return l.name
+}
+
+// Keystrokes returns the keystrokes that produce the given character. Characters that are composed using a dead key
+// require two keystrokes.
+func (l *Layout) Keystrokes(r rune) ([]uinput.Keystroke, bool) {
+	strokes, ok := l.strokes[r]
+	if !ok {
+		return nil, false
+	}
+	return append([]uinput.Keystroke(nil), strokes...), true
+}
+
+// Keys returns the keystrokes of all characters that can be typed using the layout.
+func (l *Layout) Keys() map[rune][]uinput.Keystroke {
+	keys := make(map[rune][]uinput.Keystroke, len(l.strokes))
+	for r, strokes := range l.strokes {
+		keys[r] = append([]uinput.Keystroke(nil), strokes...)
+	}
+	return keys
+}
@@ -0,0 +1,196 @@
+package xkb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bendahl/uinput"
+)
+
+func TestLoadLayout(t *testing.T) {
+	key := func(key int, modifiers uinput.Modifier) []uinput.Keystroke {
+		return []uinput.Keystroke{{Key: key, Modifiers: modifiers}}
+	}
+
+	tests := []struct {
+		layout   string
+		variant  string
+		char     rune
+		expected []uinput.Keystroke
+	}{
+		{"us", "", 'a', key(uinput.KeyA, 0)},
+		{"us", "", '~', key(uinput.KeyGrave, uinput.ModShift)},
+		{"us", "", ' ', key(uinput.KeySpace, 0)},
+		{"us", "", '\n', key(uinput.KeyEnter, 0)},
+
+		{"de", "", 'z', key(uinput.KeyY, 0)},
+		{"de", "", 'ß', key(uinput.KeyMinus, 0)},
+		{"de", "", 'Ä', key(uinput.KeyApostrophe, uinput.ModShift)},
+		{"de", "", '@', key(uinput.KeyQ, uinput.ModAltGr)},
+		{"de", "", '|', key(uinput.Key102Nd, uinput.ModAltGr)},
+		{"de", "", '€', key(uinput.KeyE, uinput.ModAltGr)},
+		{"de", "", '¿', key(uinput.KeyMinus, uinput.ModShift|uinput.ModAltGr)},
+		{"de", "", '′', key(uinput.KeyGrave, uinput.ModAltGr)},
+		{"de", "nodeadkeys", '^', key(uinput.KeyGrave, 0)},
+		{"de", "nodeadkeys", '´', key(uinput.KeyEqual, 0)},
+
+		// dead keys
+		{"de", "", 'é', []uinput.Keystroke{{Key: uinput.KeyEqual}, {Key: uinput.KeyE}}},
+		{"de", "", 'È', []uinput.Keystroke{{Key: uinput.KeyEqual, Modifiers: uinput.ModShift}, {Key: uinput.KeyE, Modifiers: uinput.ModShift}}},
+		{"de", "", 'ô', []uinput.Keystroke{{Key: uinput.KeyGrave}, {Key: uinput.KeyO}}},
+		{"de", "", '^', []uinput.Keystroke{{Key: uinput.KeyGrave}, {Key: uinput.KeyGrave}}},
+		{"de", "", '´', []uinput.Keystroke{{Key: uinput.KeyEqual}, {Key: uinput.KeyEqual}}},
+		{"de", "", 'ë', []uinput.Keystroke{{Key: uinput.KeyLeftbrace, Modifiers: uinput.ModAltGr}, {Key: uinput.KeyE}}},
+		{"de", "", 'ü', key(uinput.KeyLeftbrace, 0)},
+		{"fr", "", 'é', key(uinput.Key2, 0)},
+		{"fr", "", 'ê', []uinput.Keystroke{{Key: uinput.KeyLeftbrace}, {Key: uinput.KeyE}}},
+		{"fr", "", 'Ï', []uinput.Keystroke{{Key: uinput.KeyLeftbrace, Modifiers: uinput.ModShift}, {Key: uinput.KeyI, Modifiers: uinput.ModShift}}},
+		{"fr", "", 'ò', []uinput.Keystroke{{Key: uinput.KeyBackslash, Modifiers: uinput.ModAltGr}, {Key: uinput.KeyO}}},
+		{"fr", "", 'á', []uinput.Keystroke{{Key: uinput.KeyM, Modifiers: uinput.ModAltGr}, {Key: uinput.KeyQ}}},
+		{"fr", "", '~', key(uinput.KeyGrave, uinput.ModShift)},
+
+		{"custom", "", 'æ', key(uinput.KeyA, uinput.ModAltGr)},
+		{"custom", "", 'Æ', key(uinput.KeyA, uinput.ModShift|uinput.ModAltGr)},
+		{"custom", "", 'D', key(uinput.KeyD, uinput.ModShift)},
+		{"custom", "", 'ð', key(uinput.KeyD, uinput.ModAltGr)},
+		{"custom", "", 'f', key(uinput.KeyF, 0)},
+		{"custom", "", 'đ', key(uinput.KeyF, uinput.ModAltGr)},
+		{"custom", "", '€', key(uinput.KeyQ, 0)},
+		{"custom", "augmented", '€', key(uinput.KeyQ, 0)},
+		{"custom", "augmented", 'Ω', key(uinput.KeyQ, uinput.ModShift|uinput.ModAltGr)},
+		{"custom", "augmented", '¹', key(uinput.Key1, uinput.ModAltGr)},
+	}
+
+	for _, test := range tests {
+		layout, err := LoadLayout("testdata/symbols", test.layout, test.variant)
+		if err != nil {
+			t.Fatalf("Failed to load layout %s(%s): %v", test.layout, test.variant, err)
+		}
+		actual, ok := layout.Keystrokes(test.char)
+		if !ok {
+			t.Fatalf("Expected %q to be supported by layout %q", test.char, layout.Name())
+		}
+		if !reflect.DeepEqual(test.expected, actual) {
+			t.Fatalf("Layout %q, character %q\nExpected: %+v\nActual: %+v", layout.Name(), test.char, test.expected, actual)
+		}
+	}
+}
+
+func TestKeyboardTypesDeadKeysOfLoadedLayouts(t *testing.T) {
+	tests := []struct {
+		layout string
+		keys   []uint16
+	}{
+		// dead_acute followed by e
+		{"de", []uint16{uinput.KeyEqual, uinput.KeyE}},
+		// typed directly, although the layout has dead keys
+		{"fr", []uint16{uinput.Key2}},
+	}
+
+	for _, test := range tests {
+		layout, err := LoadLayout("testdata/symbols", test.layout, "")
+		if err != nil {
+			t.Fatalf("Failed to load layout %s: %v", test.layout, err)
+		}
+		fake := uinput.NewFakeBackend()
+		vk, err := uinput.CreateKeyboard("/dev/uinput", []byte("Test Keyboard"),
+			uinput.WithFakeBackend(fake), uinput.WithLayout(layout))
+		if err != nil {
+			t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+		}
+
+		err = vk.Type("é")
+		if err != nil {
+			t.Fatalf("Failed to type text. Last error was: %s\n", err)
+		}
+		vk.Close()
+
+		var expected []uinput.Event
+		for _, key := range test.keys {
+			expected = append(expected,
+				uinput.Event{Type: uinput.EvKey, Code: key, Value: 1}, uinput.Event{Type: uinput.EvSyn, Code: uinput.SynReport},
+				uinput.Event{Type: uinput.EvKey, Code: key, Value: 0}, uinput.Event{Type: uinput.EvSyn, Code: uinput.SynReport})
+		}
+		if actual := fake.Events(); !reflect.DeepEqual(expected, actual) {
+			t.Fatalf("Layout %q\nExpected: %+v\nActual: %+v", test.layout, expected, actual)
+		}
+	}
+}
+
+func TestLoadLayoutSkipsUnsupportedCharacters(t *testing.T) {
+	tests := []struct {
+		layout  string
+		variant string
+		char    rune
+	}{
+		// dead keys other than grave, acute, circumflex, tilde and diaeresis
+		{"de", "", 'ç'},
+		{"de", "", '¸'},
+		// the second group
+		{"custom", "", 'с'},
+		// keys that have been replaced
+		{"custom", "", 'q'},
+		{"custom", "augmented", 'q'},
+	}
+
+	for _, test := range tests {
+		layout, err := LoadLayout("testdata/symbols", test.layout, test.variant)
+		if err != nil {
+			t.Fatalf("Failed to load layout %s(%s): %v", test.layout, test.variant, err)
+		}
+		if strokes, ok := layout.Keystrokes(test.char); ok {
+			t.Fatalf("Expected %q not to be supported by layout %q, but got %+v", test.char, layout.Name(), strokes)
+		}
+	}
+}
+
+func TestLayoutKeysCoverPrintableASCII(t *testing.T) {
+	layout, err := LoadLayout("testdata/symbols", "us", "")
+	if err != nil {
+		t.Fatalf("Failed to load layout: %v", err)
+	}
+	keys := layout.Keys()
+	for r := rune(' '); r <= '~'; r++ {
+		if _, ok := keys[r]; !ok {
+			t.Fatalf("Expected %q to be part of the layout", r)
+		}
+	}
+	if layout.Name() != "us" {
+		t.Fatalf("Unexpected name: %q", layout.Name())
+	}
+}
+
+func TestLoadLayoutFailsOnMissingFilesAndSections(t *testing.T) {
+	for _, layout := range [][2]string{{"xx", ""}, {"de", "no-such-variant"}, {"../testdata/symbols/de", ""}} {
+		_, err := LoadLayout("testdata/symbols", layout[0], layout[1])
+		if err == nil {
+			t.Fatalf("Expected loading %s(%s) to fail", layout[0], layout[1])
+		}
+	}
+}
+
+func TestKeysymRune(t *testing.T) {
+	tests := []struct {
+		keysym   string
+		expected rune
+		ok       bool
+	}{
+		{"a", 'a', true},
+		{"9", '9', true},
+		{"adiaeresis", 'ä', true},
+		{"EuroSign", '€', true},
+		{"U2033", '″', true},
+		{"0x1001E9E", 'ẞ', true},
+		{"0xe4", 'ä', true},
+		{"dead_acute", 0, false},
+		{"ISO_Level3_Shift", 0, false},
+		{"NoSymbol", 0, false},
+	}
+
+	for _, test := range tests {
+		r, ok := keysymRune(test.keysym)
+		if r != test.expected || ok != test.ok {
+			t.Fatalf("Expected %q to map to %q (%v), but got %q (%v)", test.keysym, test.expected, test.ok, r, ok)
+		}
+	}
+}
@@ -0,0 +1,417 @@
+package xkb
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// noSymbol marks a level that does not produce a keysym.
+const noSymbol = "NoSymbol"
+
+// maxIncludeDepth limits nested includes, which guards against include cycles.
+const maxIncludeDepth = 16
+
+type token struct {
+	text string
+	line int
+}
+
+// isString reports whether the token is a quoted string.
+func (t token) isString() bool {
+	return strings.HasPrefix(t.text, `"`)
+}
+
+// isKeyName reports whether the token is the name of a key, such as <AC01>.
+func (t token) isKeyName() bool {
+	return strings.HasPrefix(t.text, "<")
+}
+
+// value returns the contents of a quoted string or a key name.
+func (t token) value() string {
+	return t.text[1 : len(t.text)-1]
+}
+
+func isWordChar(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// tokenize splits the contents of a symbols file into words, quoted strings, key names and punctuation, skipping
+// whitespace and comments.
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+	runes := []rune(src)
+	line := 1
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == '\n':
+			line++
+			i++
+		case unicode.IsSpace(r):
+			i++
+		case r == '#' || (r == '/' && i+1 < len(runes) && runes[i+1] == '/'):
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			end := strings.Index(string(runes[i+2:]), "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated comment", line)
+			}
+			comment := []rune(string(runes[i+2:])[:end])
+			line += strings.Count(string(comment), "\n")
+			i += len(comment) + 4
+		case r == '"' || r == '<':
+			closing := '"'
+			if r == '<' {
+				closing = '>'
+			}
+			j := i + 1
+			for j < len(runes) && runes[j] != closing && runes[j] != '\n' {
+				j++
+			}
+			if j == len(runes) || runes[j] != closing {
+				return nil, fmt.Errorf("line %d: missing %q", line, closing)
+			}
+			tokens = append(tokens, token{text: string(runes[i : j+1]), line: line})
+			i = j + 1
+		case isWordChar(r):
+			j := i
+			for j < len(runes) && isWordChar(runes[j]) {
+				j++
+			}
+			tokens = append(tokens, token{text: string(runes[i:j]), line: line})
+			i = j
+		default:
+			tokens = append(tokens, token{text: string(r), line: line})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+// symbolsFile holds the sections (xkb_symbols blocks) of a symbols file.
+type symbolsFile struct {
+	sections map[string][]token
+	// defaultSection is the section flagged as default, or the first section of the file.
+	defaultSection string
+}
+
+// parseSymbolsFile splits a symbols file into its sections.
+func parseSymbolsFile(src string) (*symbolsFile, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &symbolsFile{sections: make(map[string][]token)}
+	isDefault := false
+	for i := 0; i < len(tokens); i++ {
+		switch tokens[i].text {
+		case "default":
+			isDefault = true
+			continue
+		case "xkb_symbols":
+		default:
+			// other flags, like partial or alphanumeric_keys
+			continue
+		}
+
+		if i+2 >= len(tokens) || !tokens[i+1].isString() || tokens[i+2].text != "{" {
+			return nil, fmt.Errorf("line %d: expected section name and body after xkb_symbols", tokens[i].line)
+		}
+		name := tokens[i+1].value()
+		end, err := closingBracket(tokens, i+2)
+		if err != nil {
+			return nil, err
+		}
+		file.sections[name] = tokens[i+3 : end]
+		if isDefault || file.defaultSection == "" {
+			file.defaultSection = name
+		}
+		isDefault = false
+		i = end
+		if i+1 < len(tokens) && tokens[i+1].text == ";" {
+			i++
+		}
+	}
+	if len(file.sections) == 0 {
+		return nil, fmt.Errorf("no xkb_symbols section found")
+	}
+	return file, nil
+}
+
+// closingBracket returns the index of the bracket that closes the bracket at index start.
+func closingBracket(tokens []token, start int) (int, error) {
+	depth := 0
+	for i := start; i < len(tokens); i++ {
+		switch tokens[i].text {
+		case "{", "[", "(":
+			depth++
+		case "}", "]", ")":
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("line %d: missing closing bracket for %q", tokens[start].line, tokens[start].text)
+}
+
+// splitTopLevel splits tokens at the given separator, ignoring separators that are nested in brackets.
+func splitTopLevel(tokens []token, separator string) [][]token {
+	var parts [][]token
+	depth, start := 0, 0
+	for i, t := range tokens {
+		switch t.text {
+		case "{", "[", "(":
+			depth++
+		case "}", "]", ")":
+			depth--
+		case separator:
+			if depth == 0 {
+				parts = append(parts, tokens[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, tokens[start:])
+}
+
+// mergeMode determines how definitions of a key are combined with earlier definitions of the same key.
+type mergeMode int
+
+const (
+	// mergeOverride replaces the keysyms of all levels that are defined again.
+	mergeOverride mergeMode = iota
+	// mergeAugment only adds keysyms to levels that have not been defined yet.
+	mergeAugment
+	// mergeReplace discards the earlier definition.
+	mergeReplace
+)
+
+// keyMap maps key names to the keysyms of each shift level of the first group.
+type keyMap map[string][]string
+
+// merge combines the given levels of a key with its current definition.
+func (m keyMap) merge(key string, levels []string, mode mergeMode) {
+	if mode == mergeReplace {
+		m[key] = append([]string(nil), levels...)
+		return
+	}
+	current := m[key]
+	for i, sym := range levels {
+		if sym == noSymbol {
+			continue
+		}
+		for len(current) <= i {
+			current = append(current, noSymbol)
+		}
+		if mode == mergeAugment && current[i] != noSymbol {
+			continue
+		}
+		current[i] = sym
+	}
+	m[key] = current
+}
+
+// mergeAll merges all keys of other.
+func (m keyMap) mergeAll(other keyMap, mode mergeMode) {
+	for key, levels := range other {
+		m.merge(key, levels, mode)
+	}
+}
+
+// symbolsLoader reads sections from the symbols files of a directory, resolving includes.
+type symbolsLoader struct {
+	dir   string
+	files map[string]*symbolsFile
+}
+
+func newSymbolsLoader(dir string) *symbolsLoader {
+	return &symbolsLoader{dir: dir, files: make(map[string]*symbolsFile)}
+}
+
+func (l *symbolsLoader) file(name string) (*symbolsFile, error) {
+	if f, ok := l.files[name]; ok {
+		return f, nil
+	}
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return nil, fmt.Errorf("invalid symbols file name %q", name)
+	}
+	src, err := ioutil.ReadFile(filepath.Join(l.dir, name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read symbols file: %w", err)
+	}
+	f, err := parseSymbolsFile(string(src))
+	if err != nil {
+		return nil, fmt.Errorf("invalid symbols file %s: %w", name, err)
+	}
+	l.files[name] = f
+	return f, nil
+}
+
+// load returns the keys defined by a section of a symbols file. The default section is used if section is empty.
+func (l *symbolsLoader) load(fileName, section string, depth int) (keyMap, error) {
+	if depth > maxIncludeDepth {
+		return nil, fmt.Errorf("includes nested too deeply in %s(%s)", fileName, section)
+	}
+	f, err := l.file(fileName)
+	if err != nil {
+		return nil, err
+	}
+	if section == "" {
+		section = f.defaultSection
+	}
+	body, ok := f.sections[section]
+	if !ok {
+		return nil, fmt.Errorf("section %q not found in symbols file %s", section, fileName)
+	}
+
+	keys := make(keyMap)
+	for _, statement := range splitTopLevel(body, ";") {
+		// include statements do not need to be terminated, so a statement may start with any number of includes
+		for len(statement) > 1 {
+			mode, ok := statementMode(statement[0])
+			if !ok || !statement[1].isString() {
+				break
+			}
+			included, err := l.include(statement[1].value(), mode, depth)
+			if err != nil {
+				return nil, fmt.Errorf("failed to include %s in %s(%s): %w", statement[1].text, fileName, section, err)
+			}
+			keys.mergeAll(included, mode)
+			statement = statement[2:]
+		}
+		if len(statement) == 0 {
+			continue
+		}
+
+		mode, ok := statementMode(statement[0])
+		if ok && statement[0].text != "include" {
+			statement = statement[1:]
+		}
+		if len(statement) > 1 && statement[0].text == "key" && statement[1].isKeyName() {
+			levels, err := parseKey(statement[2:])
+			if err != nil {
+				return nil, fmt.Errorf("invalid symbols file %s: %w", fileName, err)
+			}
+			if levels != nil {
+				keys.merge(statement[1].value(), levels, mode)
+			}
+		}
+		// all other statements (names, key types, modifier maps, ...) do not affect the characters of a key
+	}
+	return keys, nil
+}
+
+// statementMode returns the merge mode a statement starts with. Statements without a merge mode use mergeOverride.
+func statementMode(t token) (mergeMode, bool) {
+	switch t.text {
+	case "include", "override":
+		return mergeOverride, true
+	case "augment":
+		return mergeAugment, true
+	case "replace":
+		return mergeReplace, true
+	}
+	return mergeOverride, false
+}
+
+// include loads an include statement like "latin(type4)+level3(ralt_switch)". Parts that are joined using '|' are
+// merged in augment mode, all others are merged in override mode.
+func (l *symbolsLoader) include(spec string, mode mergeMode, depth int) (keyMap, error) {
+	keys := make(keyMap)
+	for spec != "" {
+		end := strings.IndexAny(spec[1:], "+|") + 1
+		if end == 0 {
+			end = len(spec)
+		}
+		part := spec[:end]
+		switch part[0] {
+		case '+':
+			mode, part = mergeOverride, part[1:]
+		case '|':
+			mode, part = mergeAugment, part[1:]
+		}
+		spec = spec[end:]
+
+		// a part like "us:2" is mapped to another group, which is ignored since only the first group is used
+		if i := strings.IndexByte(part, ':'); i >= 0 {
+			if part[i+1:] != "1" {
+				continue
+			}
+			part = part[:i]
+		}
+		fileName, section := part, ""
+		if i := strings.IndexByte(part, '('); i >= 0 && strings.HasSuffix(part, ")") {
+			fileName, section = part[:i], part[i+1:len(part)-1]
+		}
+		included, err := l.load(fileName, section, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		keys.mergeAll(included, mode)
+	}
+	return keys, nil
+}
+
+// parseKey returns the keysyms of the first group from the body of a key statement, for example
+// { [ a, A, ae, AE ] } or { type[Group1]="FOUR_LEVEL", symbols[Group1]= [ a, A, ae, AE ] }. The result is nil if the
+// key does not define the first group.
+func parseKey(tokens []token) ([]string, error) {
+	if len(tokens) == 0 || tokens[0].text != "{" {
+		return nil, fmt.Errorf("expected key definition in curly brackets")
+	}
+	end, err := closingBracket(tokens, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	unnamed := 0
+	for _, element := range splitTopLevel(tokens[1:end], ",") {
+		switch {
+		case len(element) > 0 && element[0].text == "[":
+			// groups without a name are numbered in order
+			unnamed++
+			if unnamed == 1 {
+				return parseLevels(element)
+			}
+		case len(element) > 5 && element[0].text == "symbols" && element[1].text == "[":
+			group := element[2].text
+			if group != "Group1" && group != "1" {
+				continue
+			}
+			if element[3].text != "]" || element[4].text != "=" {
+				return nil, fmt.Errorf("line %d: invalid symbols definition", element[0].line)
+			}
+			return parseLevels(element[5:])
+		}
+	}
+	return nil, nil
+}
+
+// parseLevels returns the keysyms of a list of levels, such as [ a, A ].
+func parseLevels(tokens []token) ([]string, error) {
+	end, err := closingBracket(tokens, 0)
+	if err != nil {
+		return nil, err
+	}
+	if tokens[0].text != "[" || end != len(tokens)-1 {
+		return nil, fmt.Errorf("line %d: invalid list of keysyms", tokens[0].line)
+	}
+
+	var levels []string
+	for _, level := range splitTopLevel(tokens[1:end], ",") {
+		if len(level) == 1 {
+			levels = append(levels, level[0].text)
+		} else {
+			// empty levels, as well as levels with more than one keysym, are not supported
+			levels = append(levels, noSymbol)
+		}
+	}
+	return levels, nil
+}
@@ -0,0 +1,97 @@
+package xkb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSymbolsFile(t *testing.T) {
+	file, err := parseSymbolsFile(`
+partial xkb_symbols "first" {
+    key <AC01> { [ a, A ] }; // comment
+};
+default xkb_symbols "second" {
+    /* multi-line
+       comment */ include "first"
+    key <AC01> { [ b ] };
+};`)
+	if err != nil {
+		t.Fatalf("Failed to parse symbols file: %v", err)
+	}
+	if file.defaultSection != "second" {
+		t.Fatalf("Expected the section flagged as default to be the default, but got %q", file.defaultSection)
+	}
+	if len(file.sections) != 2 || file.sections["second"][0].line != 7 {
+		t.Fatalf("Unexpected sections: %+v", file.sections)
+	}
+}
+
+func TestParseSymbolsFileRejectsInvalidFiles(t *testing.T) {
+	for _, src := range []string{
+		``,
+		`xkb_symbols "unterminated" { key <AC01> { [ a ] };`,
+		`xkb_symbols { };`,
+		`xkb_symbols "key" { key <AC01 { [ a ] }; };`,
+		`/* unterminated comment`,
+	} {
+		_, err := parseSymbolsFile(src)
+		if err == nil {
+			t.Fatalf("Expected %q to be rejected", src)
+		}
+	}
+}
+
+func TestKeyMapMerge(t *testing.T) {
+	keys := keyMap{"AC01": {"a", "A"}}
+
+	keys.merge("AC01", []string{noSymbol, "B", "ae"}, mergeOverride)
+	if expected := []string{"a", "B", "ae"}; !reflect.DeepEqual(expected, keys["AC01"]) {
+		t.Fatalf("Expected: %v\nActual: %v", expected, keys["AC01"])
+	}
+	keys.merge("AC01", []string{"c", "C", "oe", "OE"}, mergeAugment)
+	if expected := []string{"a", "B", "ae", "OE"}; !reflect.DeepEqual(expected, keys["AC01"]) {
+		t.Fatalf("Expected: %v\nActual: %v", expected, keys["AC01"])
+	}
+	keys.merge("AC01", []string{"d"}, mergeReplace)
+	if expected := []string{"d"}; !reflect.DeepEqual(expected, keys["AC01"]) {
+		t.Fatalf("Expected: %v\nActual: %v", expected, keys["AC01"])
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected []string
+	}{
+		{`{ [ a, A ] }`, []string{"a", "A"}},
+		{`{ [ a, A ], [ b, B ] }`, []string{"a", "A"}},
+		{`{ type[Group1]="TWO_LEVEL", symbols[Group1]= [ a, A ] }`, []string{"a", "A"}},
+		{`{ symbols[Group2]= [ b, B ], symbols[1]= [ a, A ] }`, []string{"a", "A"}},
+		{`{ [ a, { b, c }, , d ] }`, []string{"a", noSymbol, noSymbol, "d"}},
+		{`{ type="ONE_LEVEL", actions[Group1]= [ SetMods(modifiers=Shift) ] }`, nil},
+	}
+
+	for _, test := range tests {
+		tokens, err := tokenize(test.src)
+		if err != nil {
+			t.Fatalf("Failed to tokenize %q: %v", test.src, err)
+		}
+		levels, err := parseKey(tokens)
+		if err != nil {
+			t.Fatalf("Failed to parse %q: %v", test.src, err)
+		}
+		if !reflect.DeepEqual(test.expected, levels) {
+			t.Fatalf("Key %s\nExpected: %v\nActual: %v", test.src, test.expected, levels)
+		}
+	}
+}
+
+func TestIncludeCyclesAreRejected(t *testing.T) {
+	loader := newSymbolsLoader("testdata/symbols")
+	loader.files["cycle"], _ = parseSymbolsFile(`xkb_symbols "basic" { include "cycle" };`)
+
+	_, err := loader.load("cycle", "", 0)
+	if err == nil {
+		t.Fatalf("Expected the include cycle to be rejected")
+	}
+}